@@ -0,0 +1,337 @@
+package fortune
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config 运势模型的权重配置
+// 默认值就是原来写死在算法里的数, 配置文件里只需要写想改的字段
+type Config struct {
+	Power     PowerConfig     `json:"power"`     // 命盘五行力量
+	Base      BaseConfig      `json:"base"`      // 命盘基础分
+	ShengKe   ShengKeConfig   `json:"shengKe"`   // 五行生克评分
+	Influence InfluenceConfig `json:"influence"` // 大运流年对日主的影响系数
+	Relation  RelationConfig  `json:"relation"`  // 合冲加减分
+	ShenSha   ShenShaConfig   `json:"shenSha"`   // 神煞
+	Balance   BalanceConfig   `json:"balance"`   // 五行平衡
+	DaYun     DaYunConfig     `json:"daYun"`     // 大运进程
+	Age       AgeConfig       `json:"age"`       // 年龄阶段
+	KLine     KLineConfig     `json:"kLine"`     // K线生成
+	LiuYue    LiuYueConfig    `json:"liuYue"`    // 流月
+	LiuRi     LiuRiConfig     `json:"liuRi"`     // 流日
+	Domain    DomainsConfig   `json:"domain"`    // 分项运势
+	ScoreMin  float64         `json:"scoreMin"`  // 年度评分下限
+	ScoreMax  float64         `json:"scoreMax"`  // 年度评分上限
+}
+
+// PowerConfig 命盘五行力量, 顺序为 年 月 日 时
+type PowerConfig struct {
+	Gan [4]float64 `json:"gan"` // 天干力量
+	Zhi [4]float64 `json:"zhi"` // 地支力量
+}
+
+// BaseConfig 命盘基础分, 日主加印星的占比判断身旺身弱
+type BaseConfig struct {
+	Normal      float64 `json:"normal"`      // 中和
+	Strong      float64 `json:"strong"`      // 身旺
+	Weak        float64 `json:"weak"`        // 身弱
+	StrongRatio float64 `json:"strongRatio"` // 高于此占比为身旺
+	WeakRatio   float64 `json:"weakRatio"`   // 低于此占比为身弱
+}
+
+// ShengKeConfig 五行生克评分, 以日主为我
+type ShengKeConfig struct {
+	BeSheng float64 `json:"beSheng"` // 他生我
+	Sheng   float64 `json:"sheng"`   // 我生他
+	Ke      float64 `json:"ke"`      // 我克他
+	BeKe    float64 `json:"beKe"`    // 他克我
+	Same    float64 `json:"same"`    // 比和
+}
+
+// InfluenceConfig 大运流年的生克评分乘的系数
+type InfluenceConfig struct {
+	DaYunGan       float64 `json:"daYunGan"`       // 大运天干
+	DaYunZhi       float64 `json:"daYunZhi"`       // 大运地支
+	PreDaYunGan    float64 `json:"preDaYunGan"`    // 未起运时月柱天干
+	PreDaYunZhi    float64 `json:"preDaYunZhi"`    // 未起运时月柱地支
+	LiuNianGan     float64 `json:"liuNianGan"`     // 流年天干
+	LiuNianZhi     float64 `json:"liuNianZhi"`     // 流年地支
+	DaYunShenSha   float64 `json:"daYunShenSha"`   // 大运神煞
+	LiuNianShenSha float64 `json:"liuNianShenSha"` // 流年神煞
+	Balance        float64 `json:"balance"`        // 五行平衡
+	Age            float64 `json:"age"`            // 年龄阶段
+}
+
+// RelationConfig 合冲加减分
+type RelationConfig struct {
+	GanHe       float64 `json:"ganHe"`       // 大运流年天干合化
+	GanHeBuHua  float64 `json:"ganHeBuHua"`  // 大运流年天干合而不化
+	GanHeZheng  float64 `json:"ganHeZheng"`  // 大运流年天干争合妒合, 合不成
+	ZhiHe       float64 `json:"zhiHe"`       // 大运流年地支相合
+	GanChong    float64 `json:"ganChong"`    // 大运流年天干相冲
+	ZhiChong    float64 `json:"zhiChong"`    // 大运流年地支相冲
+	DayZhiChong float64 `json:"dayZhiChong"` // 流年冲日支
+	DayZhiHe    float64 `json:"dayZhiHe"`    // 流年合日支
+
+	NatalZhiChong float64 `json:"natalZhiChong"` // 流年引动命局地支相冲, 按两柱的距离打折
+	NatalZhiHe    float64 `json:"natalZhiHe"`    // 流年引动命局地支相合, 按两柱的距离打折
+}
+
+// ShenShaConfig 神煞评分
+type ShenShaConfig struct {
+	Weights     map[string]float64 `json:"weights"`     // 各神煞的分数
+	DoubleNoble float64            `json:"doubleNoble"` // 天乙天德同见
+	JiShenBonus float64            `json:"jiShenBonus"` // 吉神多于凶神时每多一个的加分
+	Min         float64            `json:"min"`         // 下限
+	Max         float64            `json:"max"`         // 上限
+}
+
+// BalanceConfig 流年五行在命盘中的占比
+type BalanceConfig struct {
+	WeakRatio   float64 `json:"weakRatio"`   // 低于此占比为补弱
+	StrongRatio float64 `json:"strongRatio"` // 高于此占比为过旺
+	Weak        float64 `json:"weak"`        // 补弱五行的分数
+	Strong      float64 `json:"strong"`      // 过旺五行的分数
+}
+
+// DaYunConfig 大运进程
+type DaYunConfig struct {
+	Early  float64 `json:"early"`  // 大运前5年, 天干主事
+	Late   float64 `json:"late"`   // 大运后5年, 地支主事
+	Change float64 `json:"change"` // 换运之年
+}
+
+// AgeConfig 年龄阶段, 每10岁一段, 70岁以后取最后一段
+type AgeConfig struct {
+	Effect [8]float64 `json:"effect"`
+}
+
+// KLineConfig K线生成
+type KLineConfig struct {
+	PrevClose  float64 `json:"prevClose"`  // 开盘价里上一年收盘价的占比
+	NextYear   float64 `json:"nextYear"`   // 收盘价里下一年预估的占比
+	ChangeDrop float64 `json:"changeDrop"` // 即将换运时下一年预估乘的系数
+}
+
+// LiuYueConfig 流月评分, 在年度评分的基础上加减
+type LiuYueConfig struct {
+	Gan         float64 `json:"gan"`         // 流月天干生克评分的系数
+	Zhi         float64 `json:"zhi"`         // 流月地支生克评分的系数
+	ShenSha     float64 `json:"shenSha"`     // 流月神煞评分的系数
+	DayZhiChong float64 `json:"dayZhiChong"` // 流月冲日支
+	DayZhiHe    float64 `json:"dayZhiHe"`    // 流月合日支
+	SuiChong    float64 `json:"suiChong"`    // 流月冲流年地支
+	SuiHe       float64 `json:"suiHe"`       // 流月合流年地支
+}
+
+// LiuRiConfig 流日评分, 在所在流月评分的基础上加减
+type LiuRiConfig struct {
+	Gan         float64 `json:"gan"`         // 日干生克评分的系数
+	Zhi         float64 `json:"zhi"`         // 日支生克评分的系数
+	ShenSha     float64 `json:"shenSha"`     // 流日神煞评分的系数
+	DayZhiChong float64 `json:"dayZhiChong"` // 流日冲命盘日支
+	DayZhiHe    float64 `json:"dayZhiHe"`    // 流日合命盘日支
+	YueChong    float64 `json:"yueChong"`    // 流日冲流月地支
+	YueHe       float64 `json:"yueHe"`       // 流日合流月地支
+}
+
+// DomainsConfig 分项运势
+type DomainsConfig struct {
+	Career             DomainConfig `json:"career"`             // 事业
+	Wealth             DomainConfig `json:"wealth"`             // 财运
+	RelationshipMale   DomainConfig `json:"relationshipMale"`   // 男命感情, 配偶星是财星
+	RelationshipFemale DomainConfig `json:"relationshipFemale"` // 女命感情, 配偶星是官杀
+	Health             DomainConfig `json:"health"`             // 健康
+	Study              DomainConfig `json:"study"`              // 学业
+	DaYun              float64      `json:"daYun"`              // 大运一柱的系数
+	LiuNian            float64      `json:"liuNian"`            // 流年一柱的系数
+	LiuYue             float64      `json:"liuYue"`             // 流月一柱的系数
+	LiuRi              float64      `json:"liuRi"`              // 流日一柱的系数
+}
+
+// DomainConfig 某一项运势的加减分, 都是行运一柱相对日主的
+type DomainConfig struct {
+	ShiShen     map[string]float64 `json:"shiShen"`     // 十神的分数, 比如 正官
+	ShenSha     map[string]float64 `json:"shenSha"`     // 神煞的分数
+	DayGanChong float64            `json:"dayGanChong"` // 冲日干
+	DayZhiChong float64            `json:"dayZhiChong"` // 冲日支(配偶宫)
+	DayZhiHe    float64            `json:"dayZhiHe"`    // 合日支(配偶宫)
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		Power: PowerConfig{
+			Gan: [4]float64{10, 12, 15, 10}, // 月柱权重更大, 日主权重最大
+			Zhi: [4]float64{8, 10, 8, 8},    // 月令权重更大
+		},
+		Base: BaseConfig{
+			Normal:      50,
+			Strong:      55,
+			Weak:        45,
+			StrongRatio: 0.4,
+			WeakRatio:   0.25,
+		},
+		ShengKe: ShengKeConfig{
+			BeSheng: 12,
+			Sheng:   -3,
+			Ke:      8,
+			BeKe:    -10,
+			Same:    6,
+		},
+		Influence: InfluenceConfig{
+			DaYunGan:       0.25,
+			DaYunZhi:       0.20,
+			PreDaYunGan:    0.12,
+			PreDaYunZhi:    0.10,
+			LiuNianGan:     0.15,
+			LiuNianZhi:     0.10,
+			DaYunShenSha:   0.6,
+			LiuNianShenSha: 0.8,
+			Balance:        0.3,
+			Age:            0.3,
+		},
+		Relation: RelationConfig{
+			GanHe:       7,
+			GanHeBuHua:  3,
+			GanHeZheng:  0,
+			ZhiHe:       5,
+			GanChong:    -7,
+			ZhiChong:    -9,
+			DayZhiChong: -6,
+			DayZhiHe:    4,
+
+			NatalZhiChong: -4,
+			NatalZhiHe:    3,
+		},
+		ShenSha: ShenShaConfig{
+			Weights: map[string]float64{
+				"天乙贵人": 12,
+				"天德贵人": 10,
+				"月德贵人": 9,
+				"文昌贵人": 8,
+				"禄神":   8,
+				"将星":   6,
+				"华盖":   4,
+				"驿马":   5,
+				"羊刃":   -8,
+				"孤辰":   -6,
+				"寡宿":   -6,
+				"劫煞":   -7,
+				"亡神":   -7,
+				"天罗地网": -9,
+				"桃花":   3,
+			},
+			DoubleNoble: 5,
+			JiShenBonus: 2,
+			Min:         -20,
+			Max:         30,
+		},
+		Balance: BalanceConfig{
+			WeakRatio:   0.15,
+			StrongRatio: 0.3,
+			Weak:        8,
+			Strong:      -5,
+		},
+		DaYun: DaYunConfig{
+			Early:  1.5,
+			Late:   0.8,
+			Change: -4,
+		},
+		Age: AgeConfig{
+			Effect: [8]float64{5, 3, 8, 10, 6, 4, 2, 0},
+		},
+		KLine: KLineConfig{
+			PrevClose:  0.3,
+			NextYear:   0.3,
+			ChangeDrop: 0.9,
+		},
+		LiuYue: LiuYueConfig{
+			Gan:         0.4,
+			Zhi:         0.3,
+			ShenSha:     0.3,
+			DayZhiChong: -4,
+			DayZhiHe:    3,
+			SuiChong:    -3,
+			SuiHe:       2,
+		},
+		LiuRi: LiuRiConfig{
+			Gan:         0.3,
+			Zhi:         0.2,
+			ShenSha:     0.2,
+			DayZhiChong: -3,
+			DayZhiHe:    2,
+			YueChong:    -2,
+			YueHe:       1,
+		},
+		Domain: DomainsConfig{
+			Career: DomainConfig{
+				ShiShen: map[string]float64{"正官": 8, "七杀": 5, "伤官": -6, "正印": 2, "偏印": 1},
+				ShenSha: map[string]float64{"天乙贵人": 4, "将星": 5, "禄神": 3},
+			},
+			Wealth: DomainConfig{
+				ShiShen: map[string]float64{"正财": 8, "偏财": 7, "食神": 3, "伤官": 2, "劫财": -7, "比肩": -4},
+				ShenSha: map[string]float64{"禄神": 3, "驿马": 2},
+			},
+			RelationshipMale: DomainConfig{
+				ShiShen:     map[string]float64{"正财": 8, "偏财": 4, "劫财": -6, "比肩": -3},
+				ShenSha:     map[string]float64{"桃花": 4, "孤辰": -5, "寡宿": -5},
+				DayZhiChong: -8,
+				DayZhiHe:    6,
+			},
+			RelationshipFemale: DomainConfig{
+				ShiShen:     map[string]float64{"正官": 8, "七杀": 4, "伤官": -7, "食神": -2},
+				ShenSha:     map[string]float64{"桃花": 4, "孤辰": -5, "寡宿": -5},
+				DayZhiChong: -8,
+				DayZhiHe:    6,
+			},
+			Health: DomainConfig{
+				ShiShen:     map[string]float64{"七杀": -5, "正印": 3, "偏印": 2, "比肩": 2, "劫财": 1},
+				ShenSha:     map[string]float64{"羊刃": -5, "天德贵人": 3, "月德贵人": 3, "天乙贵人": 2},
+				DayGanChong: -5,
+				DayZhiChong: -8,
+			},
+			Study: DomainConfig{
+				ShiShen: map[string]float64{"正印": 8, "偏印": 6, "食神": 3, "伤官": 2, "正财": -5, "偏财": -3},
+				ShenSha: map[string]float64{"文昌贵人": 8, "华盖": 3},
+			},
+			DaYun:   0.6,
+			LiuNian: 1,
+			LiuYue:  0.8,
+			LiuRi:   0.5,
+		},
+		ScoreMin: 15,
+		ScoreMax: 95,
+	}
+}
+
+// LoadConfigFile 从 JSON 文件读取多套配置, 文件内容是 模型名 => 配置
+// 每套配置都在默认配置的基础上覆盖, 没写的字段保持默认值
+//
+//	{
+//	    "default": {},
+//	    "strong-dayun": {"influence": {"daYunGan": 0.35, "daYunZhi": 0.30}}
+//	}
+func LoadConfigFile(path string) (map[string]*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawList map[string]json.RawMessage
+	if err := json.Unmarshal(data, &rawList); err != nil {
+		return nil, fmt.Errorf("无效的运势配置文件 %s: %v", path, err)
+	}
+
+	configList := make(map[string]*Config, len(rawList))
+	for name, raw := range rawList {
+		cfg := DefaultConfig()
+		if err := json.Unmarshal(raw, cfg); err != nil {
+			return nil, fmt.Errorf("无效的运势配置 %s: %v", name, err)
+		}
+		configList[name] = cfg
+	}
+	return configList, nil
+}
@@ -0,0 +1,70 @@
+package fortune
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadConfigFile 每套配置在默认配置上覆盖, 没写的字段保持默认值
+func TestLoadConfigFile(t *testing.T) {
+	strDir := t.TempDir()
+	strPath := filepath.Join(strDir, "fortune.json")
+	strJSON := `{
+		"default": {},
+		"strong-dayun": {
+			"influence": {"daYunGan": 0.35, "daYunZhi": 0.30},
+			"relation": {"zhiChong": -12}
+		}
+	}`
+	if err := os.WriteFile(strPath, []byte(strJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	configList, err := LoadConfigFile(strPath)
+	if err != nil || len(configList) != 2 {
+		t.Fatalf("LoadConfigFile = %v %v", configList, err)
+	}
+	pDefault := DefaultConfig()
+	pStrong := configList["strong-dayun"]
+	cases := []struct {
+		strName   string
+		got, want float64
+	}{
+		{"default influence.daYunGan", configList["default"].Influence.DaYunGan, pDefault.Influence.DaYunGan},
+		{"strong-dayun influence.daYunGan", pStrong.Influence.DaYunGan, 0.35},
+		{"strong-dayun influence.daYunZhi", pStrong.Influence.DaYunZhi, 0.30},
+		{"strong-dayun relation.zhiChong", pStrong.Relation.ZhiChong, -12},
+		// 同一段里没写的字段也保持默认
+		{"strong-dayun relation.ganChong", pStrong.Relation.GanChong, pDefault.Relation.GanChong},
+		{"strong-dayun kLine.prevClose", pStrong.KLine.PrevClose, pDefault.KLine.PrevClose},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.strName, c.got, c.want)
+		}
+	}
+
+	// 文件不存在, 不是 JSON, 某一套配置的字段类型不对
+	errorCases := []struct {
+		strFile   string
+		strJSON   string
+		strSubstr string
+	}{
+		{"none.json", "", "no such file"},
+		{"bad.json", `{"default": `, "无效的运势配置文件"},
+		{"type.json", `{"default": {"relation": {"zhiChong": "-12"}}}`, "无效的运势配置 default"},
+	}
+	for _, c := range errorCases {
+		strPath := filepath.Join(strDir, c.strFile)
+		if c.strJSON != "" {
+			if err := os.WriteFile(strPath, []byte(c.strJSON), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := LoadConfigFile(strPath); err == nil || !strings.Contains(err.Error(), c.strSubstr) {
+			t.Errorf("LoadConfigFile(%s) = %v, want %s", c.strFile, err, c.strSubstr)
+		}
+	}
+}
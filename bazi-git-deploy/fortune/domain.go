@@ -0,0 +1,146 @@
+package fortune
+
+// 分项运势
+// 事业看官杀, 财运看财星, 感情看配偶星和配偶宫(日支), 学业看印星和文昌, 健康看日柱受冲.
+// 每一项都在综合评分的基础上, 按行运干支(大运 流年 流月 流日)相对日主的十神和神煞加减.
+
+import (
+	bazi "github.com/warrially/BaziGo"
+)
+
+// DomainScore 分项运势评分
+type DomainScore struct {
+	Career       float64 `json:"career"`       // 事业 官杀
+	Wealth       float64 `json:"wealth"`       // 财运 财星
+	Relationship float64 `json:"relationship"` // 感情 配偶星 配偶宫
+	Health       float64 `json:"health"`       // 健康 日柱受冲
+	Study        float64 `json:"study"`        // 学业 印星 文昌
+}
+
+// values 各项的指针, 按上面的顺序
+func (d *DomainScore) values() []*float64 {
+	return []*float64{&d.Career, &d.Wealth, &d.Relationship, &d.Health, &d.Study}
+}
+
+// domainPillar 行运的一柱, weight 是这一柱的系数
+type domainPillar struct {
+	gan     *bazi.TGan
+	zhi     *bazi.TZhi
+	shenSha *bazi.TShenSha
+	weight  float64
+}
+
+// YearDomains 计算某一年的分项评分, score 是该年的综合评分, 未起运时只看流年
+func (m *DefaultModel) YearDomains(in *YearInput, score float64) *DomainScore {
+	return m.domainScores(in, score, m.yearPillars(in)...)
+}
+
+// MonthDomains 计算一个流月的分项评分, score 是该月的综合评分
+func (m *DefaultModel) MonthDomains(in *YearInput, pLiuYue *bazi.TLiuYue, score float64) *DomainScore {
+	pillars := append(m.yearPillars(in), m.monthPillar(in, pLiuYue))
+	return m.domainScores(in, score, pillars...)
+}
+
+// DayDomains 计算一天的分项评分, score 是当天的综合评分
+func (m *DefaultModel) DayDomains(in *YearInput, pLiuYue *bazi.TLiuYue, pDayGanZhi *bazi.TGanZhi, score float64) *DomainScore {
+	gan, zhi := pDayGanZhi.ExtractGanZhi()
+	pillars := append(m.yearPillars(in), m.monthPillar(in, pLiuYue), domainPillar{
+		gan:     gan,
+		zhi:     zhi,
+		shenSha: bazi.CalcShenSha(in.DayGan, in.DayZhi, gan, zhi, "流日"),
+		weight:  m.cfg.Domain.LiuRi,
+	})
+	return m.domainScores(in, score, pillars...)
+}
+
+// yearPillars 大运和流年两柱
+func (m *DefaultModel) yearPillars(in *YearInput) []domainPillar {
+	pillars := []domainPillar{{
+		gan:     in.LiuNianGan,
+		zhi:     in.LiuNianZhi,
+		shenSha: in.LiuNianShenSha,
+		weight:  m.cfg.Domain.LiuNian,
+	}}
+	if in.InDaYun {
+		pillars = append(pillars, domainPillar{
+			gan:     in.DaYunGan,
+			zhi:     in.DaYunZhi,
+			shenSha: in.DaYunShenSha,
+			weight:  m.cfg.Domain.DaYun,
+		})
+	}
+	return pillars
+}
+
+// monthPillar 流月一柱
+func (m *DefaultModel) monthPillar(in *YearInput, pLiuYue *bazi.TLiuYue) domainPillar {
+	return domainPillar{
+		gan:     pLiuYue.Gan(),
+		zhi:     pLiuYue.Zhi(),
+		shenSha: bazi.CalcShenSha(in.DayGan, in.DayZhi, pLiuYue.Gan(), pLiuYue.Zhi(), "流月"),
+		weight:  m.cfg.Domain.LiuYue,
+	}
+}
+
+// domainScores 在综合评分的基础上按每一柱加减各项
+func (m *DefaultModel) domainScores(in *YearInput, score float64, pillars ...domainPillar) *DomainScore {
+	cfg := m.cfg.Domain
+
+	// 男命以财为妻, 女命以官杀为夫
+	relationship := cfg.RelationshipFemale
+	if in.Sex == 1 {
+		relationship = cfg.RelationshipMale
+	}
+
+	result := &DomainScore{}
+	for _, p := range []struct {
+		value *float64
+		cfg   DomainConfig
+	}{
+		{&result.Career, cfg.Career},
+		{&result.Wealth, cfg.Wealth},
+		{&result.Relationship, relationship},
+		{&result.Health, cfg.Health},
+		{&result.Study, cfg.Study},
+	} {
+		value := score
+		for _, pillar := range pillars {
+			value += m.pillarScore(in, pillar, p.cfg) * pillar.weight
+		}
+		*p.value = roundFloat(clamp(value, 0, 100), 2)
+	}
+	return result
+}
+
+// pillarScore 一柱对某一项的加减分, 天干看十神, 地支按藏干看十神, 再加神煞和对日柱的冲合
+func (m *DefaultModel) pillarScore(in *YearInput, pillar domainPillar, cfg DomainConfig) float64 {
+	nDayGan := in.DayGan.Value()
+	score := cfg.ShiShen[shiShenName(nDayGan, pillar.gan)]
+
+	pCangGan := bazi.NewCangGan(nDayGan, pillar.zhi)
+	for i := 0; i < pCangGan.Size(); i++ {
+		score += cfg.ShiShen[shiShenName(nDayGan, pCangGan.Gan(i))] * float64(pCangGan.Weight(i)) / 100
+	}
+
+	if pillar.shenSha != nil {
+		for _, ss := range pillar.shenSha.GetList() {
+			score += cfg.ShenSha[ss]
+		}
+	}
+
+	if pillar.gan.Clashes(in.DayGan) {
+		score += cfg.DayGanChong
+	}
+	if pillar.zhi.Clashes(in.DayZhi) {
+		score += cfg.DayZhiChong
+	}
+	if pillar.zhi.CombinesWith(in.DayZhi) {
+		score += cfg.DayZhiHe
+	}
+	return score
+}
+
+// shiShenName 十神全名, 比如 正官
+func shiShenName(nDayGan int, pGan *bazi.TGan) string {
+	return bazi.GetShiShenLongFromNumber(bazi.NewShiShenFromGan(nDayGan, pGan).Value())
+}
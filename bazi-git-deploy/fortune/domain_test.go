@@ -0,0 +1,80 @@
+package fortune
+
+import (
+	"testing"
+
+	bazi "github.com/warrially/BaziGo"
+)
+
+// TestYearDomains 男命 1990-5-17 8点 庚午 辛巳 壬午 甲辰, 日主壬水
+// 2024年 大运甲申 流年甲辰, 1992年未起运 流年壬申, 2020年 大运甲申 流年庚子
+// 每次只配一项分数, 其余为0, 综合评分取50
+func TestYearDomains(t *testing.T) {
+	pBazi := bazi.GetBazi(1990, 5, 17, 8, 0, 0, 1)
+	cases := []struct {
+		nYear  int
+		nSex   int
+		score  float64
+		setCfg func(cfg *DomainsConfig)
+		want   DomainScore
+	}{
+		// 戊是七杀, 辰的本气60 申的余气10, 大运系数0.6: 6 + 1*0.6
+		{2024, 1, 50, func(cfg *DomainsConfig) { cfg.Career.ShiShen = map[string]float64{"七杀": 10} },
+			DomainScore{56.6, 50, 50, 50, 50}},
+		// 甲是食神, 流年大运天干各一个: 5 + 5*0.6
+		{2024, 1, 50, func(cfg *DomainsConfig) { cfg.Wealth.ShiShen = map[string]float64{"食神": 5} },
+			DomainScore{50, 58, 50, 50, 50}},
+		// 超过100按100算
+		{2024, 1, 99, func(cfg *DomainsConfig) { cfg.Career.ShiShen = map[string]float64{"七杀": 10} },
+			DomainScore{100, 99, 99, 99, 99}},
+		// 未起运只看流年, 申的本气庚是偏印 60
+		{1992, 1, 50, func(cfg *DomainsConfig) { cfg.Study.ShiShen = map[string]float64{"偏印": 10} },
+			DomainScore{50, 50, 50, 50, 56}},
+		// 流年子冲日支午, 大运申不冲
+		{2020, 1, 50, func(cfg *DomainsConfig) { cfg.Health.DayZhiChong = -10 },
+			DomainScore{50, 50, 50, 40, 50}},
+		// 男命感情按财星那一套, 女命按官杀那一套
+		{2024, 1, 50, func(cfg *DomainsConfig) {
+			cfg.RelationshipMale.ShiShen = map[string]float64{"七杀": 10}
+			cfg.RelationshipFemale.ShiShen = map[string]float64{"七杀": 20}
+		}, DomainScore{50, 50, 56.6, 50, 50}},
+		{2024, 0, 50, func(cfg *DomainsConfig) {
+			cfg.RelationshipMale.ShiShen = map[string]float64{"七杀": 10}
+			cfg.RelationshipFemale.ShiShen = map[string]float64{"七杀": 20}
+		}, DomainScore{50, 50, 63.2, 50, 50}},
+	}
+	for _, c := range cases {
+		cfg := DefaultConfig()
+		cfg.Domain = DomainsConfig{DaYun: 0.6, LiuNian: 1, LiuYue: 0.8, LiuRi: 0.5}
+		c.setCfg(&cfg.Domain)
+		m := NewDefaultModel(DefaultModelName, cfg)
+
+		in := m.NewYearInput(pBazi, 1990, c.nYear)
+		in.Sex = c.nSex // 同一个命局只换性别, 大运不变
+		if got := m.YearDomains(in, c.score); *got != c.want {
+			t.Errorf("%d年 性别%d YearDomains = %+v, want %+v", c.nYear, c.nSex, *got, c.want)
+		}
+	}
+}
+
+// TestDayDomains 2024-01-01 甲子日, 还在2023年 癸卯 的子月 甲子, 大运甲申
+// 甲是食神, 大运 流月 流日各一个: 5*0.6 + 5*0.8 + 5*0.5
+func TestDayDomains(t *testing.T) {
+	pBazi := bazi.GetBazi(1990, 5, 17, 8, 0, 0, 1)
+	cfg := DefaultConfig()
+	cfg.Domain = DomainsConfig{DaYun: 0.6, LiuNian: 1, LiuYue: 0.8, LiuRi: 0.5}
+	cfg.Domain.Wealth.ShiShen = map[string]float64{"食神": 5}
+	m := NewDefaultModel(DefaultModelName, cfg)
+
+	in := m.NewYearInput(pBazi, 1990, 2023)
+	pLiuYue := bazi.NewLiuYue(2023, 11)
+	if pLiuYue.GanZhi().String() != "甲子" {
+		t.Fatalf("2023年子月 = %v, want 甲子", pLiuYue.GanZhi())
+	}
+	if got := m.MonthDomains(in, pLiuYue, 50).Wealth; got != 57 {
+		t.Errorf("MonthDomains Wealth = %v, want 57", got)
+	}
+	if got := m.DayDomains(in, pLiuYue, bazi.NewGanZhi(0), 50).Wealth; got != 59.5 {
+		t.Errorf("DayDomains Wealth = %v, want 59.5", got)
+	}
+}
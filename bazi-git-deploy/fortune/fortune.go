@@ -0,0 +1,92 @@
+// Package fortune 运势模型
+// 定义运势K线的数据结构和模型接口, 不同的模型(或同一算法的不同权重)注册到这里,
+// 由 /api/bazi/fortune 按名字选用, 方便对比不同的算法.
+package fortune
+
+import (
+	"sort"
+	"sync"
+
+	bazi "github.com/warrially/BaziGo"
+)
+
+// DefaultModelName 默认模型的名字
+const DefaultModelName = "default"
+
+// KLine K线数据结构
+type KLine struct {
+	Year  int     `json:"year"`  // 年份
+	Open  float64 `json:"open"`  // 开盘价(年初运势)
+	Close float64 `json:"close"` // 收盘价(年末运势)
+	High  float64 `json:"high"`  // 最高(该年最佳运势)
+	Low   float64 `json:"low"`   // 最低(该年最差运势)
+	Score float64 `json:"score"` // 综合评分
+
+	GanZhi string           `json:"ganZhi,omitempty"` // 大运 流年 流月 流日的干支, 只有 Timeline 填
+	Start  *bazi.TSolarDate `json:"start,omitempty"`  // 开始时间, 只有 Timeline 填
+	End    *bazi.TSolarDate `json:"end,omitempty"`    // 结束时间, 只有 Timeline 填
+
+	Domains *DomainScore         `json:"domains,omitempty"` // 分项运势, 模型不支持时为空
+	Events  []*bazi.TSuiYunEvent `json:"events,omitempty"`  // 岁运并临 伏吟 反吟 三刑等, 流年K线是流年带来的, 大运K线是大运带来的
+	Factors []Factor             `json:"factors,omitempty"` // 评分的每一项因素, 模型不支持时为空
+	Months  []MonthScore         `json:"months,omitempty"`  // 十二个流月的评分, 模型不支持时为空
+}
+
+// MonthScore 流月评分
+type MonthScore struct {
+	Month  int              `json:"month"`  // 八字月 1-12, 1是寅月
+	GanZhi string           `json:"ganZhi"` // 流月干支
+	Start  *bazi.TSolarDate `json:"start"`  // 交节时间, 本月开始
+	Score  float64          `json:"score"`  // 评分
+}
+
+// Factor 影响评分的一项因素
+type Factor struct {
+	Category string  `json:"category"` // 类别 命盘 大运 流年 合冲 神煞 平衡 年龄
+	Name     string  `json:"name"`     // 因素名称
+	Score    float64 `json:"score"`    // 加减分
+	Reason   string  `json:"reason"`   // 说明
+}
+
+// FortuneModel 运势模型
+type FortuneModel interface {
+	// Name 模型名字
+	Name() string
+	// Calculate 从出生年开始计算 years 年的运势K线
+	Calculate(pBazi *bazi.TBazi, birthYear int, years int) []KLine
+}
+
+var (
+	modelMu   sync.RWMutex
+	modelList = map[string]FortuneModel{}
+)
+
+// Register 注册模型, 同名的会被覆盖
+func Register(model FortuneModel) {
+	modelMu.Lock()
+	defer modelMu.Unlock()
+	modelList[model.Name()] = model
+}
+
+// Get 按名字获取模型, 名字为空时取默认模型
+func Get(name string) (FortuneModel, bool) {
+	if name == "" {
+		name = DefaultModelName
+	}
+	modelMu.RLock()
+	defer modelMu.RUnlock()
+	model, ok := modelList[name]
+	return model, ok
+}
+
+// Names 已注册的模型名字, 按字母排序
+func Names() []string {
+	modelMu.RLock()
+	defer modelMu.RUnlock()
+	names := make([]string, 0, len(modelList))
+	for name := range modelList {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,37 @@
+package fortune
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRegister 同名的模型覆盖旧的, 名字为空取默认模型, Names 按字母排序
+func TestRegister(t *testing.T) {
+	pDefault := NewDefaultModel(DefaultModelName, nil)
+	pStrong := NewDefaultModel("strong-dayun", nil)
+	Register(NewDefaultModel("b-test", nil))
+	Register(pStrong)
+	Register(NewDefaultModel(DefaultModelName, nil))
+	Register(pDefault)
+
+	cases := []struct {
+		strName string
+		want    FortuneModel
+		wantOK  bool
+	}{
+		{"", pDefault, true},
+		{DefaultModelName, pDefault, true},
+		{"strong-dayun", pStrong, true},
+		{"none", nil, false},
+	}
+	for _, c := range cases {
+		got, ok := Get(c.strName)
+		if ok != c.wantOK || (ok && got != c.want) {
+			t.Errorf("Get(%q) = %v %v, want %v %v", c.strName, got, ok, c.want, c.wantOK)
+		}
+	}
+
+	if got := strings.Join(Names(), " "); got != "b-test default strong-dayun" {
+		t.Errorf("Names = %s, want b-test default strong-dayun", got)
+	}
+}
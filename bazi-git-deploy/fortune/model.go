@@ -0,0 +1,686 @@
+package fortune
+
+// 默认运势模型
+// 综合命盘、大运、流年三者关系计算逐年运势, 算法说明见 FORTUNE_ALGORITHM.md
+
+import (
+	"fmt"
+	"strings"
+
+	bazi "github.com/warrially/BaziGo"
+)
+
+// DefaultModel 默认运势模型, 综合大运 流年 命盘 神煞, 权重来自配置
+type DefaultModel struct {
+	name string
+	cfg  *Config
+}
+
+// NewDefaultModel 新建默认运势模型, cfg 为空时使用默认配置
+func NewDefaultModel(name string, cfg *Config) *DefaultModel {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	return &DefaultModel{name: name, cfg: cfg}
+}
+
+// Calculate 用默认配置计算从出生年开始 years 年的运势K线
+func Calculate(pBazi *bazi.TBazi, birthYear int, years int) []KLine {
+	return NewDefaultModel(DefaultModelName, nil).Calculate(pBazi, birthYear, years)
+}
+
+// Name 模型名字
+func (m *DefaultModel) Name() string {
+	return m.name
+}
+
+// Config 模型使用的配置
+func (m *DefaultModel) Config() *Config {
+	return m.cfg
+}
+
+// YearInput 计算某一年运势需要的数据
+type YearInput struct {
+	DayGan            *bazi.TGan         // 日干
+	DayZhi            *bazi.TZhi         // 日支
+	DaYunGan          *bazi.TGan         // 大运天干, 未起运时为月干
+	DaYunZhi          *bazi.TZhi         // 大运地支, 未起运时为月支
+	LiuNianGan        *bazi.TGan         // 流年天干
+	LiuNianZhi        *bazi.TZhi         // 流年地支
+	Power             map[string]float64 // 八字五行力量
+	BaseScore         float64            // 命盘基础分
+	DaYunIndex        int                // 第几步大运, 未起运为-1
+	DaYunYearProgress int                // 在当前大运中的第几年
+	Age               int                // 年龄
+	InDaYun           bool               // 是否已起运
+	DaYunShenSha      *bazi.TShenSha     // 大运神煞
+	LiuNianShenSha    *bazi.TShenSha     // 流年神煞
+	HeHua             *bazi.THeHua       // 大运和流年天干相合, 未起运时是月干, 不合为空
+	GuanXiTu          *bazi.TGuanXiTu    // 命局的干支关系图, 流年引动命局的合冲按边的距离打折
+	Sex               int                // 性别 1男其他女
+}
+
+// Calculate 计算从出生年开始 years 年的运势K线
+func (m *DefaultModel) Calculate(pBazi *bazi.TBazi, birthYear int, years int) []KLine {
+	fortuneData, _ := m.calculate(pBazi, birthYear, years)
+	return fortuneData
+}
+
+// calculate 计算逐年运势K线, 同时返回每年的数据, 大运K线按它分组, 不用再算一遍
+func (m *DefaultModel) calculate(pBazi *bazi.TBazi, birthYear int, years int) ([]KLine, []*YearInput) {
+	var fortuneData []KLine
+	var inputList []*YearInput
+	cfg := m.cfg
+
+	// 用于保存前一年的收盘价，使K线连续
+	var prevClose float64 = m.BaseScore(m.BaziPower(pBazi), pBazi.SiZhu().DayZhu().Gan())
+
+	// 计算逐年运势, 每年的数据只准备一次, 下一年的留到下一轮用
+	yearInput := m.NewYearInput(pBazi, birthYear, birthYear)
+	for i := 0; i < years; i++ {
+		year := birthYear + i
+
+		// 计算该年运势评分（加入神煞影响）
+		yearScore, factors := m.YearFactors(yearInput)
+
+		// 生成K线数据 - 让K线更加平滑连续
+		// 年初运势：受前一年影响，平滑过渡
+		open := prevClose*cfg.KLine.PrevClose + yearScore*(1-cfg.KLine.PrevClose)
+
+		// 年末运势：向下一年过渡
+		nextYearBase := yearScore
+		var nextInput *YearInput
+		if i < years-1 {
+			// 预估下一年趋势, 如果即将换大运，运势波动加大
+			nextInput = m.NewYearInput(pBazi, birthYear, year+1)
+			if nextInput.DaYunIndex != yearInput.DaYunIndex && yearInput.InDaYun {
+				nextYearBase = yearScore * cfg.KLine.ChangeDrop // 换运期略有下降
+			}
+		}
+		close := yearScore*(1-cfg.KLine.NextYear) + nextYearBase*cfg.KLine.NextYear
+
+		// 年内最高点、最低点：取十二个流月里最好和最差的月份
+		months := m.MonthScores(yearInput, year, yearScore)
+		high, low := yearScore, yearScore
+		for j, month := range months {
+			if j == 0 || month.Score > high {
+				high = month.Score
+			}
+			if j == 0 || month.Score < low {
+				low = month.Score
+			}
+		}
+
+		// 确保数值在合理范围内(0-100)
+		open = clamp(open, 0, 100)
+		close = clamp(close, 0, 100)
+		high = clamp(high, 0, 100)
+		low = clamp(low, 0, 100)
+		yearScore = clamp(yearScore, 0, 100)
+
+		// 确保K线数据的逻辑正确性
+		// 首先确保high是最大值，low是最小值
+		if high < open {
+			high = open
+		}
+		if high < close {
+			high = close
+		}
+		if low > open {
+			low = open
+		}
+		if low > close {
+			low = close
+		}
+
+		// 最关键：确保low <= high，如果反了就交换
+		if low > high {
+			low, high = high, low
+		}
+
+		fortuneData = append(fortuneData, KLine{
+			Year:    year,
+			Open:    roundFloat(open, 2),
+			Close:   roundFloat(close, 2),
+			High:    roundFloat(high, 2),
+			Low:     roundFloat(low, 2),
+			Score:   roundFloat(yearScore, 2),
+			Domains: m.YearDomains(yearInput, yearScore),
+			Events:  pBazi.LiuNianSuiYun(year),
+			Factors: roundFactors(factors),
+			Months:  months,
+		})
+		inputList = append(inputList, yearInput)
+
+		// 保存本年收盘价，作为下一年开盘价的参考
+		prevClose = close
+		yearInput = nextInput
+	}
+
+	return fortuneData, inputList
+}
+
+// NewYearInput 准备出生后某一年的数据, year 早于出生年时返回 nil
+func (m *DefaultModel) NewYearInput(pBazi *bazi.TBazi, birthYear int, year int) *YearInput {
+	currentAge := year - birthYear
+	if currentAge < 0 {
+		return nil
+	}
+
+	// 获取日主天干(命主五行)
+	dayGan := pBazi.SiZhu().DayZhu().Gan()
+	dayZhi := pBazi.SiZhu().DayZhu().Zhi()
+
+	// 计算八字五行力量
+	baziPower := m.BaziPower(pBazi)
+
+	// 计算当前年龄对应的大运
+	dayunIndex, dayunYearProgress, dayunZhu := daYunOfAge(pBazi, birthYear, currentAge)
+	inDaYun := dayunIndex >= 0
+
+	// 计算流年天干地支
+	liuNianGan := GanByYear(year)
+	liuNianZhi := ZhiByYear(year)
+	pLiuNian := bazi.CombineGanZhi(liuNianGan, liuNianZhi)
+
+	return &YearInput{
+		DayGan:            dayGan,
+		DayZhi:            dayZhi,
+		DaYunGan:          dayunZhu.Gan(),
+		DaYunZhi:          dayunZhu.Zhi(),
+		LiuNianGan:        liuNianGan,
+		LiuNianZhi:        liuNianZhi,
+		Power:             baziPower,
+		BaseScore:         m.BaseScore(baziPower, dayGan), // 命盘基础分（用于判断命格强弱）
+		DaYunIndex:        dayunIndex,
+		DaYunYearProgress: dayunYearProgress,
+		Age:               currentAge,
+		InDaYun:           inDaYun,
+		DaYunShenSha:      dayunZhu.ShenSha(),                                             // 大运神煞
+		LiuNianShenSha:    bazi.CalcShenSha(dayGan, dayZhi, liuNianGan, liuNianZhi, "流年"), // 流年神煞
+		HeHua:             daYunHeHua(pBazi, dayunZhu, inDaYun, pLiuNian),
+		GuanXiTu:          pBazi.SiZhu().GuanXiTu(),
+		Sex:               pBazi.Sex(),
+	}
+}
+
+// natalEdges 命局关系图里流年地支引动的某种地支关系, 流年地支和边上的一个字相同就算引动
+func natalEdges(pGuanXiTu *bazi.TGuanXiTu, liuNianZhi *bazi.TZhi, strName string) []*bazi.TGuanXiEdge {
+	if pGuanXiTu == nil {
+		return nil
+	}
+	var result []*bazi.TGuanXiEdge
+	for _, pEdge := range pGuanXiTu.Find(strName) {
+		for _, pNode := range pEdge.NodeList() {
+			if pNode.Zhi() != nil && pNode.Zhi().Value() == liuNianZhi.Value() {
+				result = append(result, pEdge)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// daYunHeHua 大运和流年天干的合化, 放在命局里一起看挨着和争合, 未起运时用月干, 不合时返回 nil
+func daYunHeHua(pBazi *bazi.TBazi, dayunZhu *bazi.TZhu, inDaYun bool, pLiuNian *bazi.TGanZhi) *bazi.THeHua {
+	strFrom := "月干"
+	var pDaYun *bazi.TGanZhi
+	if inDaYun {
+		strFrom = "大运"
+		pDaYun = dayunZhu.GanZhi()
+	}
+	for _, pHeHua := range bazi.CalcHeHua(pBazi.SiZhu(), pDaYun, pLiuNian) {
+		if pHeHua.Has(strFrom) && pHeHua.Has("流年") {
+			return pHeHua
+		}
+	}
+	return nil
+}
+
+// daYunOfAge 某个年龄所在的大运, 返回第几步大运, 在这步大运中的第几年, 大运柱
+// 未起运时是第-1步, 用月柱作为大运, 年数就是年龄
+func daYunOfAge(pBazi *bazi.TBazi, birthYear int, age int) (int, int, *bazi.TZhu) {
+	// 获取起运年龄（起运日期年份 - 出生年份）
+	qiYunAge := pBazi.QiYunDate().Year() - birthYear
+	if qiYunAge < 0 {
+		qiYunAge = 0
+	}
+
+	if age < qiYunAge {
+		// 未起运阶段，使用月柱作为大运
+		return -1, age, pBazi.SiZhu().MonthZhu()
+	}
+
+	// 已起运，计算大运索引
+	index := (age - qiYunAge) / 10
+	if index >= 12 {
+		index = 11 // 最多12步大运
+	}
+	return index, (age - qiYunAge) % 10, pBazi.DaYun().Zhu(index)
+}
+
+// BaziPower 计算八字五行力量分布
+func (m *DefaultModel) BaziPower(pBazi *bazi.TBazi) map[string]float64 {
+	// 获取八字四柱的天干地支
+	yearGan := pBazi.SiZhu().YearZhu().Gan()
+	yearZhi := pBazi.SiZhu().YearZhu().Zhi()
+	monthGan := pBazi.SiZhu().MonthZhu().Gan()
+	monthZhi := pBazi.SiZhu().MonthZhu().Zhi()
+	dayGan := pBazi.SiZhu().DayZhu().Gan()
+	dayZhi := pBazi.SiZhu().DayZhu().Zhi()
+	hourGan := pBazi.SiZhu().HourZhu().Gan()
+	hourZhi := pBazi.SiZhu().HourZhu().Zhi()
+
+	power := map[string]float64{
+		"金": 0.0,
+		"木": 0.0,
+		"水": 0.0,
+		"火": 0.0,
+		"土": 0.0,
+	}
+
+	// 天干力量
+	ganPower := m.cfg.Power.Gan
+	addWuXingPower(power, yearGan.ToWuXing().String(), ganPower[0])
+	addWuXingPower(power, monthGan.ToWuXing().String(), ganPower[1]) // 月柱权重更大
+	addWuXingPower(power, dayGan.ToWuXing().String(), ganPower[2])   // 日主权重最大
+	addWuXingPower(power, hourGan.ToWuXing().String(), ganPower[3])
+
+	// 地支力量
+	zhiPower := m.cfg.Power.Zhi
+	addWuXingPower(power, yearZhi.ToWuXing().String(), zhiPower[0])
+	addWuXingPower(power, monthZhi.ToWuXing().String(), zhiPower[1]) // 月令权重更大
+	addWuXingPower(power, dayZhi.ToWuXing().String(), zhiPower[2])
+	addWuXingPower(power, hourZhi.ToWuXing().String(), zhiPower[3])
+
+	return power
+}
+
+// addWuXingPower 添加五行力量
+func addWuXingPower(power map[string]float64, wuXing string, value float64) {
+	if _, ok := power[wuXing]; ok {
+		power[wuXing] += value
+	}
+}
+
+// BaseScore 计算命盘基础分（判断命格强弱）
+func (m *DefaultModel) BaseScore(baziPower map[string]float64, dayGan *bazi.TGan) float64 {
+	// 计算五行总力量
+	totalPower := 0.0
+	for _, power := range baziPower {
+		totalPower += power
+	}
+
+	// 日主五行力量
+	dayWuXing := dayGan.ToWuXing().String()
+	dayPower := baziPower[dayWuXing]
+
+	// 计算生日主的五行力量（印星）
+	supportPower := baziPower[dayGan.ToWuXing().Mother().String()]
+
+	// 日主及其帮扶力量占比
+	// 帮扶力量 = 日主本身 + 同类五行（比劫） + 印星
+	// 这里dayPower已经包含了同类五行，所以再加一次就相当于：日主+比劫+印星
+	selfRatio := (dayPower*2 + supportPower) / totalPower
+
+	// 根据日主强弱确定基础分
+	// 身旺（自身强）：基础分稍高，但需要制衡
+	// 身弱（自身弱）：基础分中等，需要扶持
+	cfg := m.cfg.Base
+	baseScore := cfg.Normal
+	if selfRatio > cfg.StrongRatio {
+		baseScore = cfg.Strong // 身旺
+	} else if selfRatio < cfg.WeakRatio {
+		baseScore = cfg.Weak // 身弱
+	}
+
+	return baseScore
+}
+
+// YearScore 计算某一年的运势评分（综合大运、流年、命盘、神煞）
+func (m *DefaultModel) YearScore(in *YearInput) float64 {
+	score, _ := m.YearFactors(in)
+	return score
+}
+
+// YearFactors 计算某一年的运势评分, 同时返回每一项因素的加减分, 各项相加就是评分
+func (m *DefaultModel) YearFactors(in *YearInput) (float64, []Factor) {
+	dayGan, dayZhi := in.DayGan, in.DayZhi
+	dayunGan, dayunZhi := in.DaYunGan, in.DaYunZhi
+	liuNianGan, liuNianZhi := in.LiuNianGan, in.LiuNianZhi
+	inDaYun := in.InDaYun
+	dayunYearProgress := in.DaYunYearProgress
+
+	cfg := m.cfg
+	score := in.BaseScore
+	factors := []Factor{{Category: "命盘", Name: "基础分", Score: in.BaseScore, Reason: "日主强弱定的基准"}}
+
+	// add 记一项因素, 0分的不记
+	add := func(category, name string, value float64, reason string) {
+		if value == 0 {
+			return
+		}
+		score += value
+		factors = append(factors, Factor{Category: category, Name: name, Score: value, Reason: reason})
+	}
+
+	dayWuXing := dayGan.ToWuXing()
+	dayunGanWuXing := dayunGan.ToWuXing()
+	dayunZhiWuXing := dayunZhi.ToWuXing()
+	liuNianGanWuXing := liuNianGan.ToWuXing()
+	liuNianZhiWuXing := liuNianZhi.ToWuXing()
+
+	// 1. 大运对日主的影响（权重35%，为神煞留出空间）
+	dayunGanScore := m.ShengKeScore(dayWuXing, dayunGanWuXing)
+	dayunZhiScore := m.ShengKeScore(dayWuXing, dayunZhiWuXing)
+	if inDaYun {
+		add("大运", "大运天干生克", dayunGanScore*cfg.Influence.DaYunGan, shengKeReason(dayGan.String(), dayWuXing, dayunGan.String(), dayunGanWuXing))
+		add("大运", "大运地支生克", dayunZhiScore*cfg.Influence.DaYunZhi, shengKeReason(dayGan.String(), dayWuXing, dayunZhi.String(), dayunZhiWuXing))
+	} else {
+		// 未起运期，影响减半
+		add("大运", "月柱天干生克", dayunGanScore*cfg.Influence.PreDaYunGan, "未起运, "+shengKeReason(dayGan.String(), dayWuXing, dayunGan.String(), dayunGanWuXing))
+		add("大运", "月柱地支生克", dayunZhiScore*cfg.Influence.PreDaYunZhi, "未起运, "+shengKeReason(dayGan.String(), dayWuXing, dayunZhi.String(), dayunZhiWuXing))
+	}
+
+	// 2. 流年对日主的影响（权重25%）
+	liuNianGanScore := m.ShengKeScore(dayWuXing, liuNianGanWuXing)
+	liuNianZhiScore := m.ShengKeScore(dayWuXing, liuNianZhiWuXing)
+	add("流年", "流年天干生克", liuNianGanScore*cfg.Influence.LiuNianGan, shengKeReason(dayGan.String(), dayWuXing, liuNianGan.String(), liuNianGanWuXing))
+	add("流年", "流年地支生克", liuNianZhiScore*cfg.Influence.LiuNianZhi, shengKeReason(dayGan.String(), dayWuXing, liuNianZhi.String(), liuNianZhiWuXing))
+
+	// 3. 大运与流年的互动关系（12%）
+	// 天干合化, 合化最吉, 合而不化次之, 争合妒合合不成
+	if in.HeHua != nil {
+		reason := strings.Join(in.HeHua.Reasons(), ", ")
+		switch in.HeHua.Result() {
+		case bazi.HeHuaHua:
+			add("合冲", "天干合化", cfg.Relation.GanHe, reason)
+		case bazi.HeHuaBuHua:
+			add("合冲", "天干合而不化", cfg.Relation.GanHeBuHua, reason)
+		case bazi.HeHuaBuCheng:
+			add("合冲", "天干争合", cfg.Relation.GanHeZheng, reason)
+		}
+	}
+	// 地支六合
+	if dayunZhi.CombinesWith(liuNianZhi) {
+		add("合冲", "地支相合", cfg.Relation.ZhiHe, "大运"+dayunZhi.String()+"合流年"+liuNianZhi.String()) // 地支相合为吉
+	}
+	// 天克地冲
+	if dayunGan.Clashes(liuNianGan) {
+		add("合冲", "天干相冲", cfg.Relation.GanChong, "大运"+dayunGan.String()+"冲流年"+liuNianGan.String()) // 天干相冲为凶
+	}
+	if dayunZhi.Clashes(liuNianZhi) {
+		add("合冲", "地支相冲", cfg.Relation.ZhiChong, "大运"+dayunZhi.String()+"冲流年"+liuNianZhi.String()) // 地支相冲为大凶
+	}
+
+	// 4. 流年与命盘的互动（8%）
+	// 流年与日柱的关系
+	if liuNianZhi.Clashes(dayZhi) {
+		add("合冲", "流年冲日支", cfg.Relation.DayZhiChong, "流年"+liuNianZhi.String()+"冲日支"+dayZhi.String()) // 冲日支，身体、事业不顺
+	}
+	if liuNianZhi.CombinesWith(dayZhi) {
+		add("合冲", "流年合日支", cfg.Relation.DayZhiHe, "流年"+liuNianZhi.String()+"合日支"+dayZhi.String()) // 合日支，贵人相助
+	}
+	// 流年引动命局原有的地支冲合, 按两柱的距离打折, 挨着的不打折, 年时相隔最轻
+	for _, pEdge := range natalEdges(in.GuanXiTu, liuNianZhi, bazi.GuanXiLiuChong) {
+		add("合冲", "引动命局相冲", cfg.Relation.NatalZhiChong*float64(pEdge.Weight())/100, "流年"+liuNianZhi.String()+"引动"+pEdge.String())
+	}
+	for _, pEdge := range natalEdges(in.GuanXiTu, liuNianZhi, bazi.GuanXiLiuHe) {
+		add("合冲", "引动命局相合", cfg.Relation.NatalZhiHe*float64(pEdge.Weight())/100, "流年"+liuNianZhi.String()+"引动"+pEdge.String())
+	}
+
+	// 5. 大运神煞影响（8%权重）
+	if in.DaYunShenSha != nil {
+		shenShaScore := m.ShenShaScore(in.DaYunShenSha)
+		add("神煞", "大运神煞", shenShaScore*cfg.Influence.DaYunShenSha, shenShaReason(in.DaYunShenSha)) // 大运神煞影响持续10年
+	}
+
+	// 6. 流年神煞影响（10%权重）
+	if in.LiuNianShenSha != nil {
+		shenShaScore := m.ShenShaScore(in.LiuNianShenSha)
+		add("神煞", "流年神煞", shenShaScore*cfg.Influence.LiuNianShenSha, shenShaReason(in.LiuNianShenSha)) // 流年神煞影响当年
+	}
+
+	// 7. 八字五行平衡度（约3-5%影响）
+	balanceScore := m.BalanceScore(in.Power, liuNianGanWuXing.String())
+	if balanceScore > 0 {
+		add("平衡", "五行平衡", balanceScore*cfg.Influence.Balance, "流年"+liuNianGanWuXing.String()+"补命盘弱项")
+	} else {
+		add("平衡", "五行平衡", balanceScore*cfg.Influence.Balance, "流年"+liuNianGanWuXing.String()+"在命盘中已过旺")
+	}
+
+	// 8. 大运内部进程影响（约1.5%影响）
+	// 大运前5年和后5年运势不同
+	if inDaYun {
+		if dayunYearProgress < 5 {
+			// 大运前5年，天干主事
+			add("大运", "大运进程", cfg.DaYun.Early, "大运前5年, 天干主事")
+		} else {
+			// 大运后5年，地支主事
+			add("大运", "大运进程", cfg.DaYun.Late, "大运后5年, 地支主事")
+		}
+	}
+
+	// 9. 年龄阶段生命周期影响（约3-6%影响）
+	ageEffect := m.AgeEffect(in.Age)
+	add("年龄", "年龄阶段", ageEffect*cfg.Influence.Age, fmt.Sprintf("%d岁", in.Age))
+
+	// 10. 换大运的交接期（特殊处理）
+	if inDaYun && dayunYearProgress == 0 && in.DaYunIndex > 0 {
+		add("大运", "换运", cfg.DaYun.Change, "换运之年, 运势波动大") // 换运之年，运势波动大，通常不利
+	}
+
+	// 超出评分范围的部分单独列出, 保证各项相加等于评分
+	if clamped := clamp(score, cfg.ScoreMin, cfg.ScoreMax); clamped != score {
+		factors = append(factors, Factor{Category: "命盘", Name: "范围限制", Score: clamped - score, Reason: fmt.Sprintf("评分限制在%v-%v之间", cfg.ScoreMin, cfg.ScoreMax)})
+		score = clamped
+	}
+	return score, factors
+}
+
+// roundFactors 因素分数保留两位小数
+func roundFactors(factors []Factor) []Factor {
+	for i := range factors {
+		factors[i].Score = roundFloat(factors[i].Score, 2)
+	}
+	return factors
+}
+
+// shengKeReason 生克关系的说明, self 是日主, 说明里是 干支+五行 比如 戊土
+func shengKeReason(self string, selfWuXing *bazi.TWuXing, other string, otherWuXing *bazi.TWuXing) string {
+	self += selfWuXing.String()
+	other += otherWuXing.String()
+	switch selfWuXing.Relation(otherWuXing) {
+	case bazi.WuXingBeiSheng:
+		return other + "生日主" + self
+	case bazi.WuXingSheng:
+		return other + "泄日主" + self
+	case bazi.WuXingKe:
+		return other + "为日主" + self + "所克"
+	case bazi.WuXingBeiKe:
+		return other + "克日主" + self
+	}
+	return other + "与日主" + self + "比和"
+}
+
+// shenShaReason 神煞的说明
+func shenShaReason(shenSha *bazi.TShenSha) string {
+	return strings.Join(shenSha.GetList(), " ")
+}
+
+// ShenShaScore 计算神煞评分
+func (m *DefaultModel) ShenShaScore(shenSha *bazi.TShenSha) float64 {
+	if shenSha == nil || shenSha.Count() == 0 {
+		return 0.0
+	}
+
+	score := 0.0
+	shenShaList := shenSha.GetList()
+
+	cfg := m.cfg.ShenSha
+
+	// 累加神煞分数
+	for _, ss := range shenShaList {
+		if weight, ok := cfg.Weights[ss]; ok {
+			score += weight
+		}
+	}
+
+	// 特殊组合加成
+	// 如果同时有天乙贵人和天德贵人，额外加分
+	hasTianYi := false
+	hasTianDe := false
+	for _, ss := range shenShaList {
+		if ss == "天乙贵人" {
+			hasTianYi = true
+		}
+		if ss == "天德贵人" {
+			hasTianDe = true
+		}
+	}
+	if hasTianYi && hasTianDe {
+		score += cfg.DoubleNoble // 双贵人加成
+	}
+
+	// 如果吉神多于凶神，额外加分
+	jiShenCount := shenSha.GetJiShenCount()
+	xiongShenCount := shenSha.GetXiongShenCount()
+	if jiShenCount > xiongShenCount && jiShenCount >= 2 {
+		score += float64(jiShenCount-xiongShenCount) * cfg.JiShenBonus
+	}
+
+	return clamp(score, cfg.Min, cfg.Max)
+}
+
+// MonthScores 计算流年里十二个流月的评分, 每个月在年度评分的基础上按流月干支加减
+// 流月从立春开始, 每交一个节换一个月, 超出节气表范围时返回空
+func (m *DefaultModel) MonthScores(in *YearInput, year int, yearScore float64) []MonthScore {
+	var result []MonthScore
+	for _, pLiuYue := range bazi.GetLiuYueList(year) {
+		result = append(result, MonthScore{
+			Month:  pLiuYue.Month(),
+			GanZhi: pLiuYue.GanZhi().String(),
+			Start:  pLiuYue.StartDate(),
+			Score:  roundFloat(m.MonthScore(in, pLiuYue, yearScore), 2),
+		})
+	}
+	return result
+}
+
+// MonthScore 计算一个流月的评分, 在年度评分的基础上按流月干支加减
+func (m *DefaultModel) MonthScore(in *YearInput, pLiuYue *bazi.TLiuYue, yearScore float64) float64 {
+	cfg := m.cfg.LiuYue
+	dayWuXing := in.DayGan.ToWuXing()
+	monthGan := pLiuYue.Gan()
+	monthZhi := pLiuYue.Zhi()
+	score := yearScore
+
+	// 流月对日主的生克
+	score += m.ShengKeScore(dayWuXing, monthGan.ToWuXing()) * cfg.Gan
+	score += m.ShengKeScore(dayWuXing, monthZhi.ToWuXing()) * cfg.Zhi
+
+	// 流月与日支、流年地支的合冲
+	if monthZhi.Clashes(in.DayZhi) {
+		score += cfg.DayZhiChong
+	}
+	if monthZhi.CombinesWith(in.DayZhi) {
+		score += cfg.DayZhiHe
+	}
+	if monthZhi.Clashes(in.LiuNianZhi) {
+		score += cfg.SuiChong
+	}
+	if monthZhi.CombinesWith(in.LiuNianZhi) {
+		score += cfg.SuiHe
+	}
+
+	// 流月神煞
+	shenSha := bazi.CalcShenSha(in.DayGan, in.DayZhi, monthGan, monthZhi, "流月")
+	score += m.ShenShaScore(shenSha) * cfg.ShenSha
+
+	return clamp(score, 0, 100)
+}
+
+// DayScore 计算一天的评分, 在所在流月评分的基础上按日柱加减
+func (m *DefaultModel) DayScore(in *YearInput, pLiuYue *bazi.TLiuYue, monthScore float64, pDayGanZhi *bazi.TGanZhi) float64 {
+	cfg := m.cfg.LiuRi
+	dayWuXing := in.DayGan.ToWuXing()
+	gan, zhi := pDayGanZhi.ExtractGanZhi()
+	score := monthScore
+
+	// 日柱对日主的生克
+	score += m.ShengKeScore(dayWuXing, gan.ToWuXing()) * cfg.Gan
+	score += m.ShengKeScore(dayWuXing, zhi.ToWuXing()) * cfg.Zhi
+
+	// 日支与命盘日支、流月地支的合冲
+	if zhi.Clashes(in.DayZhi) {
+		score += cfg.DayZhiChong
+	}
+	if zhi.CombinesWith(in.DayZhi) {
+		score += cfg.DayZhiHe
+	}
+	if zhi.Clashes(pLiuYue.Zhi()) {
+		score += cfg.YueChong
+	}
+	if zhi.CombinesWith(pLiuYue.Zhi()) {
+		score += cfg.YueHe
+	}
+
+	// 流日神煞
+	shenSha := bazi.CalcShenSha(in.DayGan, in.DayZhi, gan, zhi, "流日")
+	score += m.ShenShaScore(shenSha) * cfg.ShenSha
+
+	return clamp(score, 0, 100)
+}
+
+// ShengKeScore 计算五行生克关系评分, self 为日主五行
+func (m *DefaultModel) ShengKeScore(self, other *bazi.TWuXing) float64 {
+	switch self.Relation(other) {
+	case bazi.WuXingBeiSheng:
+		return m.cfg.ShengKe.BeSheng // 被生为大吉，有贵人相助
+	case bazi.WuXingSheng:
+		return m.cfg.ShengKe.Sheng // 生他人为泄气，消耗自身能量，不利
+	case bazi.WuXingKe:
+		return m.cfg.ShengKe.Ke // 我克他人得财，有力量控制局面
+	case bazi.WuXingBeiKe:
+		return m.cfg.ShengKe.BeKe // 被克为大凶，受压制
+	}
+	return m.cfg.ShengKe.Same // 比和,有帮助
+}
+
+// BalanceScore 计算流年五行对八字五行平衡度的评分
+func (m *DefaultModel) BalanceScore(baziPower map[string]float64, liuNianWuXing string) float64 {
+	// 计算总力量
+	total := 0.0
+	for _, power := range baziPower {
+		total += power
+	}
+
+	// 计算当前五行在八字中的占比
+	currentPower := baziPower[liuNianWuXing]
+	ratio := currentPower / total
+
+	// 如果流年五行在八字中较弱,则流年补足为吉
+	cfg := m.cfg.Balance
+	if ratio < cfg.WeakRatio {
+		return cfg.Weak // 补弱五行为吉
+	} else if ratio > cfg.StrongRatio {
+		return cfg.Strong // 强者更强,过旺为凶
+	}
+
+	return 0.0
+}
+
+// AgeEffect 计算年龄阶段影响
+func (m *DefaultModel) AgeEffect(age int) float64 {
+	// 不同年龄段有不同的运势基调, 每10岁一段:
+	// 童年 青少年 青年(上升期) 壮年(高峰期) 中年(稳定期) 中老年(下降期) 老年(平稳期) 晚年(淡泊期)
+	effect := m.cfg.Age.Effect
+	index := age / 10
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(effect) {
+		index = len(effect) - 1
+	}
+	return effect[index]
+}
@@ -0,0 +1,132 @@
+package fortune
+
+import (
+	"math"
+	"testing"
+
+	bazi "github.com/warrially/BaziGo"
+)
+
+// TestNatalGuanXi 流年引动命局的冲合按两柱的距离打折, 挨着的不打折, 隔一柱60%, 年时相隔30%
+func TestNatalGuanXi(t *testing.T) {
+	cases := []struct {
+		nYear, nMonth, nDay int
+		nLiuNian            int
+		strName             string
+		wantList            []float64
+	}{
+		// 壬戌 甲辰 戊辰 戊午, 甲辰年引动年月戌辰冲(挨着)和年日戌辰冲(隔一柱)
+		{1982, 4, 15, 2024, "引动命局相冲", []float64{-4, -2.4}},
+		// 己未 丁丑 丁亥 丙午, 丙午年引动年时未午合(年时相隔)
+		{1980, 1, 15, 2026, "引动命局相合", []float64{0.9}},
+		// 庚申 己丑 癸巳 戊午, 丁巳年引动年日申巳合(隔一柱)
+		{1981, 1, 15, 2037, "引动命局相合", []float64{1.8}},
+		// 同一个命局, 没引动的年份不加分
+		{1981, 1, 15, 2024, "引动命局相合", nil},
+	}
+	m := NewDefaultModel(DefaultModelName, nil)
+	cfgNone := DefaultConfig()
+	cfgNone.Relation.NatalZhiChong = 0
+	cfgNone.Relation.NatalZhiHe = 0
+	mNone := NewDefaultModel(DefaultModelName, cfgNone)
+	for _, c := range cases {
+		pBazi := bazi.GetBazi(c.nYear, c.nMonth, c.nDay, 12, 0, 0, 1)
+		score, factors := m.YearFactors(m.NewYearInput(pBazi, c.nYear, c.nLiuNian))
+		var gotList []float64
+		for _, factor := range factors {
+			if factor.Name == c.strName {
+				gotList = append(gotList, roundFloat(factor.Score, 2))
+			}
+		}
+		if len(gotList) != len(c.wantList) {
+			t.Errorf("%d %d年 %s = %v, want %v", c.nYear, c.nLiuNian, c.strName, gotList, c.wantList)
+			continue
+		}
+		sum := 0.0
+		for i := range gotList {
+			if gotList[i] != c.wantList[i] {
+				t.Errorf("%d %d年 %s = %v, want %v", c.nYear, c.nLiuNian, c.strName, gotList, c.wantList)
+			}
+			sum += c.wantList[i]
+		}
+		// 不算引动时分数正好差这么多
+		scoreNone, _ := mNone.YearFactors(mNone.NewYearInput(pBazi, c.nYear, c.nLiuNian))
+		if math.Abs(score-scoreNone-sum) > 1e-9 {
+			t.Errorf("%d %d年 分数差 = %v, want %v", c.nYear, c.nLiuNian, score-scoreNone, sum)
+		}
+	}
+}
+
+// TestFactorSum 各项因素相加等于评分, 超出范围时多一项范围限制
+func TestFactorSum(t *testing.T) {
+	cfgNarrow := DefaultConfig()
+	cfgNarrow.ScoreMin = 45
+	cfgNarrow.ScoreMax = 50
+	cases := []struct {
+		nYear, nMonth, nDay, nHour int
+		nSex                       int
+		cfg                        *Config
+		isClamped                  bool // 是否一定有年份被限制
+	}{
+		{1990, 5, 17, 8, 1, nil, false},
+		{1990, 5, 17, 8, 0, nil, false},
+		{1982, 4, 15, 12, 1, nil, false},
+		{1990, 5, 17, 8, 1, cfgNarrow, true},
+	}
+	for _, c := range cases {
+		m := NewDefaultModel(DefaultModelName, c.cfg)
+		pBazi := bazi.GetBazi(c.nYear, c.nMonth, c.nDay, c.nHour, 0, 0, c.nSex)
+		nClamped := 0
+		for _, line := range m.Calculate(pBazi, c.nYear, 80) {
+			sum := 0.0
+			for _, factor := range line.Factors {
+				sum += factor.Score
+				if factor.Name == "范围限制" {
+					nClamped++
+				}
+			}
+			// 每项都保留了两位小数, 误差不超过每项半分
+			if math.Abs(sum-line.Score) > 0.005*float64(len(line.Factors)) {
+				t.Errorf("%d-%d-%d %d年 因素合计 = %v, want %v", c.nYear, c.nMonth, c.nDay, line.Year, sum, line.Score)
+			}
+			if line.Score < m.Config().ScoreMin || line.Score > m.Config().ScoreMax {
+				t.Errorf("%d-%d-%d %d年 评分 = %v, 超出 %v-%v", c.nYear, c.nMonth, c.nDay, line.Year, line.Score, m.Config().ScoreMin, m.Config().ScoreMax)
+			}
+		}
+		if c.isClamped && nClamped == 0 {
+			t.Errorf("%d-%d-%d 没有年份被限制在 %v-%v", c.nYear, c.nMonth, c.nDay, m.Config().ScoreMin, m.Config().ScoreMax)
+		}
+	}
+}
+
+// TestHighLow 每年的最高最低取十二个流月里最好和最差的, 开盘收盘超出时再放宽
+func TestHighLow(t *testing.T) {
+	cases := []struct {
+		nYear, nMonth, nDay, nHour int
+		nSex                       int
+	}{
+		{1990, 5, 17, 8, 1},
+		{1982, 4, 15, 12, 0},
+		{1985, 2, 3, 23, 1},
+	}
+	m := NewDefaultModel(DefaultModelName, nil)
+	for _, c := range cases {
+		pBazi := bazi.GetBazi(c.nYear, c.nMonth, c.nDay, c.nHour, 0, 0, c.nSex)
+		for _, line := range m.Calculate(pBazi, c.nYear, 80) {
+			if len(line.Months) != 12 {
+				t.Fatalf("%d-%d-%d %d年 流月 = %d, want 12", c.nYear, c.nMonth, c.nDay, line.Year, len(line.Months))
+			}
+			high, low := line.Months[0].Score, line.Months[0].Score
+			for _, month := range line.Months {
+				high = math.Max(high, month.Score)
+				low = math.Min(low, month.Score)
+			}
+			high = math.Max(high, math.Max(line.Open, line.Close))
+			low = math.Min(low, math.Min(line.Open, line.Close))
+			// 流月的评分和K线各自保留两位小数
+			if math.Abs(line.High-high) > 0.01 || math.Abs(line.Low-low) > 0.01 {
+				t.Errorf("%d-%d-%d %d年 High Low = %v %v, want %v %v", c.nYear, c.nMonth, c.nDay, line.Year, line.High, line.Low, high, low)
+			}
+		}
+	}
+}
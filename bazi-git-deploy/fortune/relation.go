@@ -0,0 +1,40 @@
+package fortune
+
+// 数值工具, 干支的合冲用 bazi.TGan bazi.TZhi 上的方法, 五行生克用 bazi.TWuXing 上的方法
+
+import (
+	"math"
+
+	bazi "github.com/warrially/BaziGo"
+)
+
+// GanByYear 根据年份获取流年天干
+func GanByYear(year int) *bazi.TGan {
+	// 计算天干索引(以1900年为庚年起点)
+	ganIndex := (year - 1900 + 6) % 10
+	return bazi.NewGan(ganIndex)
+}
+
+// ZhiByYear 根据年份获取流年地支
+func ZhiByYear(year int) *bazi.TZhi {
+	// 计算地支索引(以1900年为子年起点)
+	zhiIndex := (year - 1900 + 0) % 12
+	return bazi.NewZhi(zhiIndex)
+}
+
+// clamp 限制数值范围
+func clamp(value, min, max float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+// roundFloat 四舍五入到指定小数位
+func roundFloat(value float64, precision int) float64 {
+	ratio := math.Pow(10, float64(precision))
+	return math.Round(value*ratio) / ratio
+}
@@ -0,0 +1,307 @@
+package fortune
+
+// 多粒度运势K线
+// 同一套算法按大运 流年 流月 流日四种粒度出K线, 前端可以从一生的走势一直放大到某几天.
+// 流月按节划分, 流日按日柱, 每根K线的开盘收盘向前后相邻的K线过渡, 最高最低取下一级粒度里最好和最差的.
+
+import (
+	bazi "github.com/warrially/BaziGo"
+)
+
+// Resolution K线的时间粒度
+type Resolution string
+
+const (
+	ResolutionDecade Resolution = "decade" // 每步大运一根, 未起运的几年算一根
+	ResolutionYear   Resolution = "year"   // 每年一根
+	ResolutionMonth  Resolution = "month"  // 每个流月一根, 交节换月
+	ResolutionDay    Resolution = "day"    // 每天一根
+)
+
+// ParseResolution 解析时间粒度, 为空时是 year
+func ParseResolution(str string) (Resolution, bool) {
+	switch r := Resolution(str); r {
+	case "":
+		return ResolutionYear, true
+	case ResolutionDecade, ResolutionYear, ResolutionMonth, ResolutionDay:
+		return r, true
+	}
+	return "", false
+}
+
+// TimelineModel 支持多种时间粒度的运势模型
+type TimelineModel interface {
+	FortuneModel
+	// Timeline 计算 pStart 到 pEnd 之间按 resolution 划分的运势K线, 出生前的部分不计算
+	Timeline(pBazi *bazi.TBazi, birthYear int, resolution Resolution, pStart, pEnd *bazi.TSolarDate) []KLine
+}
+
+// Timeline 计算 pStart 到 pEnd 之间按 resolution 划分的运势K线
+// 大运和流年K线和 Calculate 的逐年K线一致, 只是多了起止时间和干支
+func (m *DefaultModel) Timeline(pBazi *bazi.TBazi, birthYear int, resolution Resolution, pStart, pEnd *bazi.TSolarDate) []KLine {
+	switch resolution {
+	case ResolutionDecade:
+		return m.decadeLines(pBazi, birthYear, pStart, pEnd)
+	case ResolutionMonth:
+		return m.monthLines(pBazi, birthYear, pStart, pEnd)
+	case ResolutionDay:
+		return m.dayLines(pBazi, birthYear, pStart, pEnd)
+	}
+	return m.yearLines(pBazi, birthYear, pStart, pEnd)
+}
+
+// yearLines 流年K线, 年份按新历年, 起止时间是立春到下一个立春
+func (m *DefaultModel) yearLines(pBazi *bazi.TBazi, birthYear int, pStart, pEnd *bazi.TSolarDate) []KLine {
+	result, _ := m.yearList(pBazi, birthYear, pStart.Year(), pEnd.Year())
+	return result
+}
+
+// yearList nStartYear 到 nEndYear 的流年K线和每年的数据
+func (m *DefaultModel) yearList(pBazi *bazi.TBazi, birthYear int, nStartYear, nEndYear int) ([]KLine, []*YearInput) {
+	if nEndYear < birthYear {
+		return nil, nil
+	}
+
+	// 多算一年, 最后一年的收盘价才会向下一年过渡, 和整段计算的结果一致
+	var result []KLine
+	var inputList []*YearInput
+	lineList, allInputList := m.calculate(pBazi, birthYear, nEndYear-birthYear+2)
+	for i, line := range lineList {
+		if line.Year < nStartYear || line.Year > nEndYear {
+			continue
+		}
+		line.GanZhi = bazi.NewGanZhiFromYear(line.Year).String()
+		if pFirst, pLast := bazi.NewLiuYue(line.Year, 1), bazi.NewLiuYue(line.Year, 12); pFirst != nil && pLast != nil {
+			line.Start = pFirst.StartDate()
+			line.End = pLast.EndDate()
+		}
+		result = append(result, line)
+		inputList = append(inputList, allInputList[i])
+	}
+	return result, inputList
+}
+
+// decadeLines 大运K线, 把同一步大运的流年K线合成一根
+func (m *DefaultModel) decadeLines(pBazi *bazi.TBazi, birthYear int, pStart, pEnd *bazi.TSolarDate) []KLine {
+	// 按整步大运取流年, 再合并
+	nStartYear := pStart.Year()
+	if nStartYear < birthYear {
+		nStartYear = birthYear
+	}
+	nEndYear := pEnd.Year()
+	if nIndex, nProgress, _ := daYunOfAge(pBazi, birthYear, nStartYear-birthYear); nIndex >= 0 {
+		nStartYear -= nProgress
+	} else {
+		nStartYear = birthYear
+	}
+	if nEndYear >= birthYear {
+		if nIndex, nProgress, _ := daYunOfAge(pBazi, birthYear, nEndYear-birthYear); nIndex >= 0 {
+			nEndYear += 9 - nProgress
+		}
+	}
+
+	var result []KLine
+	var nDaYunIndex int
+	var nScoreCount int
+	lineList, inputList := m.yearList(pBazi, birthYear, nStartYear, nEndYear)
+	for i, line := range lineList {
+		in := inputList[i]
+		domains := line.Domains
+		if len(result) == 0 || in.DaYunIndex != nDaYunIndex {
+			// 换运, 新起一根
+			nDaYunIndex = in.DaYunIndex
+			nScoreCount = 0
+			line.GanZhi = in.DaYunGan.String() + in.DaYunZhi.String()
+			line.Domains = &DomainScore{}
+			line.Events = nil
+			if in.InDaYun {
+				line.Events = pBazi.DaYunSuiYun(in.DaYunIndex)
+			}
+			line.Factors = nil
+			line.Months = nil
+			result = append(result, line)
+		}
+
+		// 开盘取第一年, 收盘取最后一年, 最高最低取整步大运, 评分取平均
+		p := &result[len(result)-1]
+		p.Close = line.Close
+		p.End = line.End
+		if line.High > p.High {
+			p.High = line.High
+		}
+		if line.Low < p.Low {
+			p.Low = line.Low
+		}
+		p.Score = (p.Score*float64(nScoreCount) + line.Score) / float64(nScoreCount+1)
+		for i, value := range p.Domains.values() {
+			*value = (*value*float64(nScoreCount) + *domains.values()[i]) / float64(nScoreCount+1)
+		}
+		nScoreCount++
+	}
+
+	for i := range result {
+		result[i].Score = roundFloat(result[i].Score, 2)
+		for _, value := range result[i].Domains.values() {
+			*value = roundFloat(*value, 2)
+		}
+	}
+	return result
+}
+
+// monthLines 流月K线, 最高最低取本月最好和最差的一天
+func (m *DefaultModel) monthLines(pBazi *bazi.TBazi, birthYear int, pStart, pEnd *bazi.TSolarDate) []KLine {
+	monthList, dayList := m.flowList(pBazi, birthYear, pStart, pEnd)
+
+	var result []KLine
+	for i, month := range monthList {
+		if !month.inRange {
+			continue
+		}
+		prev, next := month.score, month.score
+		if i > 0 {
+			prev = monthList[i-1].score
+		}
+		if i < len(monthList)-1 {
+			next = monthList[i+1].score
+		}
+		high, low := month.score, month.score
+		for _, day := range dayList[month.nFirst : month.nLast+1] {
+			high = max(high, day.score)
+			low = min(low, day.score)
+		}
+
+		line := m.flowLine(prev, month.score, next, high, low)
+		line.Year = month.pLiuYue.Year()
+		line.GanZhi = month.pLiuYue.GanZhi().String()
+		line.Start = month.pLiuYue.StartDate()
+		line.End = month.pLiuYue.EndDate()
+		line.Domains = m.MonthDomains(month.in, month.pLiuYue, month.score)
+		result = append(result, line)
+	}
+	return result
+}
+
+// dayLines 流日K线, 一天之内不再细分, 最高最低就是开盘收盘和评分里的最大最小
+func (m *DefaultModel) dayLines(pBazi *bazi.TBazi, birthYear int, pStart, pEnd *bazi.TSolarDate) []KLine {
+	monthList, dayList := m.flowList(pBazi, birthYear, pStart, pEnd)
+
+	var result []KLine
+	for i, day := range dayList {
+		nJDN := day.pDate.JulianDayNumber()
+		if nJDN < pStart.JulianDayNumber() || nJDN > pEnd.JulianDayNumber() {
+			continue
+		}
+		prev, next := day.score, day.score
+		if i > 0 {
+			prev = dayList[i-1].score
+		}
+		if i < len(dayList)-1 {
+			next = dayList[i+1].score
+		}
+
+		line := m.flowLine(prev, day.score, next, day.score, day.score)
+		month := monthList[day.nMonth]
+		line.Year = month.pLiuYue.Year()
+		line.GanZhi = day.pGanZhi.String()
+		line.Domains = m.DayDomains(month.in, month.pLiuYue, day.pGanZhi, day.score)
+		line.Start = day.pDate
+		line.End = bazi.NewSolarDateFromJulianDay(float64(nJDN) + 0.5)
+		result = append(result, line)
+	}
+	return result
+}
+
+// flowLine 流月流日的K线, 开盘从上一根过渡, 收盘向下一根过渡, 系数和逐年K线一样
+func (m *DefaultModel) flowLine(prev, score, next, high, low float64) KLine {
+	cfg := m.cfg.KLine
+	open := prev*cfg.PrevClose + score*(1-cfg.PrevClose)
+	close := score*(1-cfg.NextYear) + next*cfg.NextYear
+	high = max(high, open, close)
+	low = min(low, open, close)
+
+	return KLine{
+		Open:  roundFloat(open, 2),
+		Close: roundFloat(close, 2),
+		High:  roundFloat(high, 2),
+		Low:   roundFloat(low, 2),
+		Score: roundFloat(score, 2),
+	}
+}
+
+// flowMonth 流月及其评分
+type flowMonth struct {
+	pLiuYue *bazi.TLiuYue
+	in      *YearInput // 所在流年的数据
+	score   float64
+	inRange bool // 是否和要计算的时间段重叠
+	nFirst  int  // 本月第一天在流日列表里的下标
+	nLast   int  // 本月最后一天在流日列表里的下标
+}
+
+// flowDay 流日及其评分
+type flowDay struct {
+	pDate   *bazi.TSolarDate // 当天零点
+	pGanZhi *bazi.TGanZhi    // 日柱
+	nMonth  int              // 所在流月在流月列表里的下标
+	score   float64
+}
+
+// flowList 计算覆盖 pStart 到 pEnd 的流月和流日, pEnd 当天也算在内
+// 前后各多算一个月, 给两头的K线过渡用, 出生那年之前的不算
+func (m *DefaultModel) flowList(pBazi *bazi.TBazi, birthYear int, pStart, pEnd *bazi.TSolarDate) ([]flowMonth, []flowDay) {
+	nStartJDN := pStart.JulianDayNumber()
+	nEndJDN := pEnd.JulianDayNumber()
+
+	// 八字年从立春开始, 新历年初的日期属于上一个八字年
+	var monthList []flowMonth
+	nFirst, nLast := -1, -1
+	for nYear := pStart.Year() - 1; nYear <= pEnd.Year(); nYear++ {
+		in := m.NewYearInput(pBazi, birthYear, nYear)
+		if in == nil {
+			continue
+		}
+		yearScore, _ := m.YearFactors(in)
+
+		for _, pLiuYue := range bazi.GetLiuYueList(nYear) {
+			month := flowMonth{
+				pLiuYue: pLiuYue,
+				in:      in,
+				score:   m.MonthScore(in, pLiuYue, yearScore),
+				inRange: pLiuYue.StartDate().JulianDayNumber() <= nEndJDN && pLiuYue.EndDate().JulianDayNumber() > nStartJDN,
+			}
+			if month.inRange {
+				if nFirst < 0 {
+					nFirst = len(monthList)
+				}
+				nLast = len(monthList)
+			}
+			monthList = append(monthList, month)
+		}
+	}
+	if nFirst < 0 {
+		return nil, nil
+	}
+
+	// 前后各多留一个月
+	nFirst = max(nFirst-1, 0)
+	nLast = min(nLast+1, len(monthList)-1)
+	monthList = monthList[nFirst : nLast+1]
+
+	// 交节那天算新的月份
+	var dayList []flowDay
+	for i := range monthList {
+		month := &monthList[i]
+		month.nFirst = len(dayList)
+		for nJDN := month.pLiuYue.StartDate().JulianDayNumber(); nJDN < month.pLiuYue.EndDate().JulianDayNumber(); nJDN++ {
+			pGanZhi := bazi.NewGanZhiFromJulianDay(nJDN)
+			dayList = append(dayList, flowDay{
+				pDate:   bazi.NewSolarDateFromJulianDay(float64(nJDN) - 0.5),
+				pGanZhi: pGanZhi,
+				nMonth:  i,
+				score:   m.DayScore(month.in, month.pLiuYue, month.score, pGanZhi),
+			})
+		}
+		month.nLast = len(dayList) - 1
+	}
+	return monthList, dayList
+}
@@ -0,0 +1,108 @@
+package fortune
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	bazi "github.com/warrially/BaziGo"
+)
+
+// TestParseResolution 为空时是 year, 其他的不认
+func TestParseResolution(t *testing.T) {
+	cases := []struct {
+		str    string
+		want   Resolution
+		wantOK bool
+	}{
+		{"", ResolutionYear, true},
+		{"decade", ResolutionDecade, true},
+		{"year", ResolutionYear, true},
+		{"month", ResolutionMonth, true},
+		{"day", ResolutionDay, true},
+		{"week", "", false},
+	}
+	for _, c := range cases {
+		if got, ok := ParseResolution(c.str); got != c.want || ok != c.wantOK {
+			t.Errorf("ParseResolution(%q) = %q %v, want %q %v", c.str, got, ok, c.want, c.wantOK)
+		}
+	}
+}
+
+// TestTimeline 男命 1990-5-17 8点 庚午 辛巳 壬午 甲辰, 大运 甲申 2016年起, 乙酉 2026年起
+func TestTimeline(t *testing.T) {
+	pBazi := bazi.GetBazi(1990, 5, 17, 8, 0, 0, 1)
+	m := NewDefaultModel(DefaultModelName, nil)
+	yearList := m.Calculate(pBazi, 1990, 50)
+
+	cases := []struct {
+		resolution   Resolution
+		pStart, pEnd *bazi.TSolarDate
+		strGanZhi    string // 每根K线的干支
+		strStart     string // 第一根的开始时间
+		strEnd       string // 最后一根的结束时间
+	}{
+		// 整步大运, 起止是交运那年的立春
+		{ResolutionDecade, bazi.NewSolarDate(2020, 1, 1, 0, 0, 0), bazi.NewSolarDate(2030, 12, 31, 0, 0, 0),
+			"甲申 乙酉", "2016-02-04 17:46:00", "2036-02-04 14:19:25"},
+		// 立春到下一个立春
+		{ResolutionYear, bazi.NewSolarDate(2023, 1, 1, 0, 0, 0), bazi.NewSolarDate(2025, 1, 1, 0, 0, 0),
+			"癸卯 甲辰 乙巳", "2023-02-04 10:42:21", "2026-02-04 04:01:51"},
+		// 新历年初还是上一年的子月丑月, 交节换月
+		{ResolutionMonth, bazi.NewSolarDate(2024, 1, 1, 0, 0, 0), bazi.NewSolarDate(2024, 3, 1, 0, 0, 0),
+			"甲子 乙丑 丙寅", "2023-12-07 17:32:44", "2024-03-05 10:22:31"},
+		// 零点到第二天零点, 结束那天也算
+		{ResolutionDay, bazi.NewSolarDate(2024, 1, 1, 0, 0, 0), bazi.NewSolarDate(2024, 1, 3, 0, 0, 0),
+			"甲子 乙丑 丙寅", "2024-01-01 00:00:00", "2024-01-04 00:00:00"},
+	}
+	for _, c := range cases {
+		lineList := m.Timeline(pBazi, 1990, c.resolution, c.pStart, c.pEnd)
+		var strList []string
+		for _, line := range lineList {
+			strList = append(strList, line.GanZhi)
+		}
+		if got := strings.Join(strList, " "); got != c.strGanZhi {
+			t.Errorf("%s GanZhi = %s, want %s", c.resolution, got, c.strGanZhi)
+			continue
+		}
+		strStart, _ := lineList[0].Start.MarshalText()
+		strEnd, _ := lineList[len(lineList)-1].End.MarshalText()
+		if string(strStart) != c.strStart || string(strEnd) != c.strEnd {
+			t.Errorf("%s Start End = %s %s, want %s %s", c.resolution, strStart, strEnd, c.strStart, c.strEnd)
+		}
+		// 前后相接, 最高最低包住开盘收盘
+		for i, line := range lineList {
+			if i > 0 && *line.Start != *lineList[i-1].End {
+				t.Errorf("%s %s Start = %v, want %v", c.resolution, line.GanZhi, line.Start, lineList[i-1].End)
+			}
+			if line.High < math.Max(line.Open, line.Close) || line.Low > math.Min(line.Open, line.Close) {
+				t.Errorf("%s %s = %+v", c.resolution, line.GanZhi, line)
+			}
+		}
+	}
+
+	// 流年K线和整段计算的一样
+	for _, line := range m.Timeline(pBazi, 1990, ResolutionYear, bazi.NewSolarDate(2023, 1, 1, 0, 0, 0), bazi.NewSolarDate(2025, 1, 1, 0, 0, 0)) {
+		want := yearList[line.Year-1990]
+		if line.Open != want.Open || line.Close != want.Close || line.High != want.High || line.Low != want.Low || line.Score != want.Score {
+			t.Errorf("%d年 = %+v, want %+v", line.Year, line, want)
+		}
+	}
+
+	// 大运K线开盘取第一年, 收盘取最后一年, 评分取平均
+	for _, line := range m.Timeline(pBazi, 1990, ResolutionDecade, bazi.NewSolarDate(2020, 1, 1, 0, 0, 0), bazi.NewSolarDate(2030, 12, 31, 0, 0, 0)) {
+		first, last := yearList[line.Year-1990], yearList[line.Year-1990+9]
+		sum := 0.0
+		for _, year := range yearList[line.Year-1990 : line.Year-1990+10] {
+			sum += year.Score
+		}
+		if line.Open != first.Open || line.Close != last.Close || line.Score != roundFloat(sum/10, 2) {
+			t.Errorf("%s Open Close Score = %v %v %v, want %v %v %v", line.GanZhi, line.Open, line.Close, line.Score, first.Open, last.Close, roundFloat(sum/10, 2))
+		}
+	}
+
+	// 出生前没有K线
+	if got := m.Timeline(pBazi, 1990, ResolutionYear, bazi.NewSolarDate(1980, 1, 1, 0, 0, 0), bazi.NewSolarDate(1989, 1, 1, 0, 0, 0)); len(got) != 0 {
+		t.Errorf("出生前 = %v", got)
+	}
+}
@@ -0,0 +1,45 @@
+package fortune
+
+// 五行强度走势
+// 命局的五行强度见 bazi.TXiYong, 这里逐年把大运和流年两柱加进命局再算一遍, 用的是同一张强度表.
+
+import (
+	bazi "github.com/warrially/BaziGo"
+)
+
+// WuXingStrength 某一年的五行强度
+type WuXingStrength struct {
+	Year     int    `json:"year"`     // 年份
+	Age      int    `json:"age"`      // 年龄
+	DaYun    string `json:"daYun"`    // 大运干支, 未起运时为空
+	LiuNian  string `json:"liuNian"`  // 流年干支
+	Strength [5]int `json:"strength"` // 五行强度, 金木水火土
+}
+
+// WuXingSeries 从出生年开始 years 年的五行强度, 每年是命局加上当年的大运和流年
+// weighted 时按命局月令的旺相休囚死和人元司令加权
+func WuXingSeries(pBazi *bazi.TBazi, birthYear int, years int, weighted bool) []WuXingStrength {
+	var result []WuXingStrength
+	for age := 0; age < years; age++ {
+		year := birthYear + age
+		item := WuXingStrength{
+			Year:    year,
+			Age:     age,
+			LiuNian: bazi.NewGanZhiFromYear(year).String(),
+		}
+
+		pGanZhiList := []*bazi.TGanZhi{bazi.NewGanZhiFromYear(year)}
+		if index, _, pZhu := daYunOfAge(pBazi, birthYear, age); index >= 0 {
+			item.DaYun = pZhu.GanZhi().String()
+			pGanZhiList = append(pGanZhiList, pZhu.GanZhi())
+		}
+
+		pXiYong := bazi.NewXiYongWithGanZhi(pBazi.SiZhu(), pGanZhiList...)
+		if weighted {
+			pXiYong = pXiYong.Weighted()
+		}
+		item.Strength = pXiYong.WuXingList()
+		result = append(result, item)
+	}
+	return result
+}
@@ -42,6 +42,7 @@ type TBazi struct {
 	nSex       int         // 性别1男其他女
 	pDaYun     *TDaYun     // 大运
 	pQiYunDate *TSolarDate // 起运时间XX年XX月开始起运
+	pLiuQin    *TLiuQin    // 六亲
 }
 
 // 八字初始化
@@ -76,6 +77,12 @@ func (m *TBazi) init() *TBazi {
 		m.pDaYun.nAge[i] = nAge + 10*i
 	}
 
+	// 6. 调候用神出现在哪几步大运
+	m.pSiZhu.TiaoHou().setDaYun(m.pDaYun)
+
+	// 7. 六亲, 要用到性别和大运
+	m.pLiuQin = NewLiuQin(m)
+
 	return m
 }
 
@@ -104,11 +111,21 @@ func (m *TBazi) LunarDate() *TLunarDate {
 	return m.pLunarDate
 }
 
+// Sex 性别 1男其他女
+func (m *TBazi) Sex() int {
+	return m.nSex
+}
+
 // DaYun 获取大运
 func (m *TBazi) DaYun() *TDaYun {
 	return m.pDaYun
 }
 
+// LiuQin 六亲
+func (m *TBazi) LiuQin() *TLiuQin {
+	return m.pLiuQin
+}
+
 // QiYunDate 起运时间
 func (m *TBazi) QiYunDate() *TSolarDate {
 	return m.pQiYunDate
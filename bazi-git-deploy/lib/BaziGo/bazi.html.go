@@ -53,6 +53,37 @@ func (m *TBazi) ToHTML() string {
 	ganzhi(fmt.Sprintf("%02d日", m.Date().Day()), m.LunarDate().Day(), m.SiZhu().DayZhu()).AddTo(row)
 	ganzhi(fmt.Sprintf("%02d时", m.Date().Hour()), m.LunarDate().Hour(), m.SiZhu().HourZhu()).AddTo(row)
 
+	// 旺相休囚死 和 人元司令
+	row = htmlgo.NewRow().SetPadding("10px").AddTo(html.GetBody())
+	for i, pWangShuai := range m.SiZhu().WangShuaiList() {
+		pWuXing := NewWuXing(i)
+		htmlgo.NewDiv().SetMargin("0px 10px 0px 0px").AddTo(row).AddChild(
+			htmlgo.NewFont().SetText(pWuXing.String() + pWangShuai.String()).SetSize(3).SetColor(pWuXing.Color()))
+	}
+	htmlgo.NewDiv().SetMargin("0px 10px 0px 0px").AddTo(row).AddChild(
+		htmlgo.NewFont().SetText(m.SiZhu().SiLing().String()).SetSize(3).SetColor("gray"))
+	htmlgo.NewDiv().AddTo(row).AddChild(
+		htmlgo.NewFont().SetText(m.SiZhu().TiaoHou().String()).SetSize(3).SetColor("gray"))
+
+	// 十神统计, 每个十神一行, 条的长短是强度
+	pTongJi := m.SiZhu().ShiShenTongJi()
+	nMaxWeight := pTongJi.MaxWeight()
+	for _, item := range pTongJi.ItemList() {
+		row = htmlgo.NewRow().SetPadding("2px 10px").AddTo(html.GetBody())
+		strText := fmt.Sprintf("%s 透%d 藏%d", GetShiShenLongFromNumber(item.ShiShen().Value()), item.Tou(), item.Cang())
+		if item.IsMissing() {
+			strText = GetShiShenLongFromNumber(item.ShiShen().Value()) + " 缺"
+		}
+		htmlgo.NewDiv().SetMargin("0px 10px 0px 0px").SetFlex(1).AddTo(row).AddChild(
+			htmlgo.NewFont().SetText(strText).SetSize(2).SetColor(item.WuXing().Color()))
+
+		bar := htmlgo.NewRow().SetFlex(4).AddTo(row)
+		htmlgo.NewDiv().SetBackground(item.WuXing().Color()).SetHeight("12px").SetFlex(item.Weight()).AddTo(bar)
+		htmlgo.NewDiv().SetFlex(nMaxWeight - item.Weight()).AddTo(bar)
+		htmlgo.NewDiv().SetMargin("0px 0px 0px 10px").SetFlex(1).AddTo(row).AddChild(
+			htmlgo.NewFont().SetText(fmt.Sprintf("%d", item.Weight())).SetSize(2).SetColor("gray"))
+	}
+
 	// 分隔符
 	htmlgo.NewDiv().SetBackground("rgb(238,238,238)").SetMargin("10px 0px").SetHeight("5px").AddTo(html.GetBody())
 
@@ -15,10 +15,68 @@ var cangganlist = [12][3]int{
 	{4, 7, 3},   // 戌土 藏干 戊土、辛金、丁火。
 	{8, 0, -1}}  // 亥水 藏干 壬水、甲木。
 
-// NewCangGan 新建藏干
+// 藏干的角色
+const (
+	CangGanBenQi   = iota // 本气
+	CangGanZhongQi        // 中气
+	CangGanYuQi           // 余气
+)
+
+// GetCangGanRoleFromNumber 从数字获得藏干角色名, 0-2
+func GetCangGanRoleFromNumber(nValue int) string {
+	switch nValue {
+	case CangGanBenQi:
+		return "本气"
+	case CangGanZhongQi:
+		return "中气"
+	case CangGanYuQi:
+		return "余气"
+	}
+	return ""
+}
+
+// 藏干的角色, 和藏干表一一对应
+// 丑辰戌和巳申的第二个藏干是上一季留下来的余气, 第三个才是中气, 比如 辰 藏 戊土本气 乙木余气 癸水中气
+// 未是木库, 第二个藏干乙木是中气, 丁火是上一季留下来的余气
+var cangganrolelist = [12][3]int{
+	{0, -1, -1}, // 子 癸本气
+	{0, 2, 1},   // 丑 己本气 癸余气 辛中气
+	{0, 1, 2},   // 寅 甲本气 丙中气 戊余气
+	{0, -1, -1}, // 卯 乙本气
+	{0, 2, 1},   // 辰 戊本气 乙余气 癸中气
+	{0, 2, 1},   // 巳 丙本气 戊余气 庚中气
+	{0, 1, -1},  // 午 丁本气 己中气
+	{0, 1, 2},   // 未 己本气 乙中气 丁余气
+	{0, 2, 1},   // 申 庚本气 戊余气 壬中气
+	{0, -1, -1}, // 酉 辛本气
+	{0, 2, 1},   // 戌 戊本气 辛余气 丁中气
+	{0, 1, -1}}  // 亥 壬本气 甲中气
+
+// TCangGanWeightList 藏干的系数表(百分比), 行是地支藏干的个数减一, 列是 本气 中气 余气
+type TCangGanWeightList [3][3]int
+
+// 藏干的系数表, 天干透出算100
+var cangganweightlist = TCangGanWeightList{
+	//本气 中气 余气
+	{100, 0, 0},  // 一个藏干
+	{70, 30, 0},  // 两个藏干
+	{60, 30, 10}} // 三个藏干
+
+// GetCangGanWeightList 默认的藏干系数表
+func GetCangGanWeightList() TCangGanWeightList {
+	return cangganweightlist
+}
+
+// NewCangGan 新建藏干, 系数按默认的系数表
 func NewCangGan(nDayGan int, pZhi *TZhi) *TCangGan {
+	return NewCangGanWithWeightList(nDayGan, pZhi, cangganweightlist)
+}
+
+// NewCangGanWithWeightList 新建藏干, 系数按传入的系数表
+func NewCangGanWithWeightList(nDayGan int, pZhi *TZhi, weightList TCangGanWeightList) *TCangGan {
 	pCangGan := &TCangGan{
-		nDayGan: nDayGan,
+		nDayGan:    nDayGan,
+		weightList: weightList,
 	}
 
 	pCangGan.init(nDayGan, pZhi)
@@ -30,7 +88,9 @@ func NewCangGan(nDayGan int, pZhi *TZhi) *TCangGan {
 type TCangGan struct {
 	cangGanList []*TGan
 	shishenList []*TShiShen
-	nDayGan     int // 记录用日干
+	roleList    []int // 本气 中气 余气
+	nDayGan     int   // 记录用日干
+	weightList  TCangGanWeightList
 }
 
 func (m *TCangGan) init(nDayGan int, pZhi *TZhi) {
@@ -43,6 +103,7 @@ func (m *TCangGan) init(nDayGan int, pZhi *TZhi) {
 			pShiShen := NewShiShenFromGan(nDayGan, pGan)
 			m.cangGanList = append(m.cangGanList, pGan)
 			m.shishenList = append(m.shishenList, pShiShen)
+			m.roleList = append(m.roleList, cangganrolelist[nZhi][i])
 			// 添加十神
 		} else {
 			break
@@ -77,6 +138,30 @@ func (m *TCangGan) ShiShen(nIdx int) *TShiShen {
 	return m.shishenList[nIdx]
 }
 
+// Role 藏干的角色 CangGanBenQi CangGanZhongQi CangGanYuQi, 索引不对返回 -1
+func (m *TCangGan) Role(nIdx int) int {
+	if nIdx < 0 {
+		return -1
+	}
+	if nIdx >= m.Size() {
+		return -1
+	}
+	return m.roleList[nIdx]
+}
+
+// RoleName 藏干的角色名 本气 中气 余气
+func (m *TCangGan) RoleName(nIdx int) string {
+	return GetCangGanRoleFromNumber(m.Role(nIdx))
+}
+
+// Weight 藏干的系数(百分比), 按藏干个数和角色查系数表
+func (m *TCangGan) Weight(nIdx int) int {
+	if nRole := m.Role(nIdx); nRole >= 0 {
+		return m.weightList[m.Size()-1][nRole]
+	}
+	return 0
+}
+
 func (m *TCangGan) String() string {
 	strResult := ""
 
@@ -0,0 +1,73 @@
+package bazi
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestCangGanRole 十二地支的藏干, 角色和系数
+func TestCangGanRole(t *testing.T) {
+	cases := []struct {
+		strZhi  string
+		strWant string // 藏干 角色 系数
+	}{
+		{"子", "癸本气100"},
+		{"丑", "己本气60 癸余气10 辛中气30"},
+		{"寅", "甲本气60 丙中气30 戊余气10"},
+		{"卯", "乙本气100"},
+		{"辰", "戊本气60 乙余气10 癸中气30"},
+		{"巳", "丙本气60 戊余气10 庚中气30"},
+		{"午", "丁本气70 己中气30"},
+		{"未", "己本气60 乙中气30 丁余气10"}, // 木库, 乙木是中气
+		{"申", "庚本气60 戊余气10 壬中气30"},
+		{"酉", "辛本气100"},
+		{"戌", "戊本气60 辛余气10 丁中气30"},
+		{"亥", "壬本气70 甲中气30"},
+	}
+	for nZhi, c := range cases {
+		pZhi := NewZhi(nZhi)
+		if pZhi.String() != c.strZhi {
+			t.Fatalf("NewZhi(%d) = %v, want %s", nZhi, pZhi, c.strZhi)
+		}
+		pCangGan := NewCangGan(0, pZhi)
+		strGot := ""
+		for i := 0; i < pCangGan.Size(); i++ {
+			if i > 0 {
+				strGot += " "
+			}
+			strGot += pCangGan.Gan(i).String() + pCangGan.RoleName(i) + fmt.Sprint(pCangGan.Weight(i))
+		}
+		if strGot != c.strWant {
+			t.Errorf("%s 藏干 = %s, want %s", c.strZhi, strGot, c.strWant)
+		}
+	}
+}
+
+// TestCangGanWeightList 传入的系数表只影响这次新建的藏干, 索引不对的系数是0
+func TestCangGanWeightList(t *testing.T) {
+	weightList := TCangGanWeightList{{100, 0, 0}, {80, 20, 0}, {50, 35, 15}}
+	pCangGan := NewCangGanWithWeightList(0, NewZhi(7), weightList) // 未
+	for i, nWant := range []int{50, 35, 15} {
+		if got := pCangGan.Weight(i); got != nWant {
+			t.Errorf("未 Weight(%d) = %d, want %d", i, got, nWant)
+		}
+	}
+	if got := pCangGan.Weight(3); got != 0 {
+		t.Errorf("未 Weight(3) = %d, want 0", got)
+	}
+	if got := pCangGan.Role(-1); got != -1 {
+		t.Errorf("未 Role(-1) = %d, want -1", got)
+	}
+	if got := NewCangGan(0, NewZhi(7)).Weight(0); got != 60 {
+		t.Errorf("默认表 未 Weight(0) = %d, want 60", got)
+	}
+
+	// 庚午 辛巳 壬午 甲辰, 正财是两个午里的丁本气
+	pSiZhu := GetBazi(1990, 5, 17, 8, 0, 0, 1).SiZhu()
+	if got := pSiZhu.ShiShenWeightListWith(weightList)[5]; got != 160 {
+		t.Errorf("ShiShenWeightListWith 正财 = %d, want 160", got)
+	}
+	if got := pSiZhu.ShiShenWeightList()[5]; got != 140 {
+		t.Errorf("ShiShenWeightList 正财 = %d, want 140", got)
+	}
+}
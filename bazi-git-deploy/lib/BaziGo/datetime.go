@@ -0,0 +1,187 @@
+package bazi
+
+// 新历/农历 与 time.Time 之间的互转, 以及 JSON / 文本序列化
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// unixEpoch64TimeStamp 1970-01-01 00:00:00 的64位时间戳
+// 64位时间戳从公元原点开始计秒, 与 Unix 时间戳只差这一个常量
+const unixEpoch64TimeStamp int64 = 719165 * 24 * 60 * 60
+
+// 日期的文本格式, 年-月-日 时:分:秒
+const dateTextFormat = "%04d-%02d-%02d %02d:%02d:%02d"
+
+// 农历闰月的文本标记, 比如 2020-闰04-01 00:00:00
+const leapMonthTextMark = "闰"
+
+// get64TimeStampFromTime time.Time 转成64位时间戳, 按 t 所在时区的钟面时间计算
+func get64TimeStampFromTime(t time.Time) int64 {
+	_, nOffset := t.Zone()
+	return t.Unix() + int64(nOffset) + unixEpoch64TimeStamp
+}
+
+// getTimeFrom64TimeStamp 64位时间戳转成 time.Time, 钟面时间落在 loc 时区, loc 为空时使用本地时区
+func getTimeFrom64TimeStamp(nTimeStamp int64, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.Local
+	}
+	// time 包使用的是格里高利历外推, 先在 UTC 下换算, 再按钟面时间放到目标时区
+	t := time.Unix(nTimeStamp-unixEpoch64TimeStamp, 0).UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+}
+
+// NewSolarDateFromTime 从 time.Time 创建新历时间
+func NewSolarDateFromTime(t time.Time) *TSolarDate {
+	return NewSolarDateFrom64TimeStamp(get64TimeStampFromTime(t))
+}
+
+// FromTime 从 time.Time 设置新历时间, 按 t 所在时区的钟面时间
+// 1582 年 10 月 15 日之前的日期会换算成儒略历
+func (m *TSolarDate) FromTime(t time.Time) *TSolarDate {
+	*m = *NewSolarDateFromTime(t)
+	return m
+}
+
+// ToTime 转成 time.Time, 钟面时间落在 loc 时区, loc 为空时使用本地时区
+func (m *TSolarDate) ToTime(loc *time.Location) time.Time {
+	return getTimeFrom64TimeStamp(m.Get64TimeStamp(), loc)
+}
+
+// Before 是否早于另一个日期
+func (m *TSolarDate) Before(other *TSolarDate) bool {
+	return m.Get64TimeStamp() < other.Get64TimeStamp()
+}
+
+// After 是否晚于另一个日期
+func (m *TSolarDate) After(other *TSolarDate) bool {
+	return m.Get64TimeStamp() > other.Get64TimeStamp()
+}
+
+// Add 加上一段时长, 返回新的日期, 不足一秒的部分忽略
+func (m *TSolarDate) Add(d time.Duration) *TSolarDate {
+	return NewSolarDateFrom64TimeStamp(m.Get64TimeStamp() + int64(d/time.Second))
+}
+
+// MarshalText 文本序列化, 格式为 年-月-日 时:分:秒
+func (m *TSolarDate) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf(dateTextFormat, m.nYear, m.nMonth, m.nDay, m.nHour, m.nMinute, m.nSecond)), nil
+}
+
+// UnmarshalText 文本反序列化, 格式同 MarshalText
+func (m *TSolarDate) UnmarshalText(text []byte) error {
+	var nYear, nMonth, nDay, nHour, nMinute, nSecond int
+	if _, err := fmt.Sscanf(string(text), "%d-%d-%d %d:%d:%d", &nYear, &nMonth, &nDay, &nHour, &nMinute, &nSecond); err != nil {
+		return fmt.Errorf("无效的新历时间 %q: %v", text, err)
+	}
+
+	pDate := NewSolarDate(nYear, nMonth, nDay, nHour, nMinute, nSecond)
+	if pDate == nil {
+		return fmt.Errorf("无效的新历时间 %q", text)
+	}
+	*m = *pDate
+	return nil
+}
+
+// MarshalJSON JSON 序列化, 输出 MarshalText 的字符串
+func (m *TSolarDate) MarshalJSON() ([]byte, error) {
+	text, _ := m.MarshalText()
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON JSON 反序列化
+func (m *TSolarDate) UnmarshalJSON(data []byte) error {
+	var strText string
+	if err := json.Unmarshal(data, &strText); err != nil {
+		return err
+	}
+	return m.UnmarshalText([]byte(strText))
+}
+
+// NewLunarDateFromTime 从 time.Time 创建农历时间, 超出农历表范围时返回 nil
+func NewLunarDateFromTime(t time.Time) *TLunarDate {
+	return NewLunarDateFrom64TimeStamp(get64TimeStampFromTime(t))
+}
+
+// FromTime 从 time.Time 设置农历时间, 超出农历表范围时返回 nil 并保持原值
+func (m *TLunarDate) FromTime(t time.Time) *TLunarDate {
+	pDate := NewLunarDateFromTime(t)
+	if pDate == nil {
+		return nil
+	}
+	*m = *pDate
+	return m
+}
+
+// ToTime 转成 time.Time, 钟面时间落在 loc 时区, loc 为空时使用本地时区
+func (m *TLunarDate) ToTime(loc *time.Location) time.Time {
+	return getTimeFrom64TimeStamp(m.Get64TimeStamp(), loc)
+}
+
+// Before 是否早于另一个日期
+func (m *TLunarDate) Before(other *TLunarDate) bool {
+	return m.Get64TimeStamp() < other.Get64TimeStamp()
+}
+
+// After 是否晚于另一个日期
+func (m *TLunarDate) After(other *TLunarDate) bool {
+	return m.Get64TimeStamp() > other.Get64TimeStamp()
+}
+
+// Add 加上一段时长, 返回新的农历日期, 超出农历表范围时返回 nil
+func (m *TLunarDate) Add(d time.Duration) *TLunarDate {
+	return NewLunarDateFrom64TimeStamp(m.Get64TimeStamp() + int64(d/time.Second))
+}
+
+// MarshalText 文本序列化, 格式为 年-月-日 时:分:秒, 月份是传统月, 闰月前面加 "闰"
+func (m *TLunarDate) MarshalText() ([]byte, error) {
+	strText := fmt.Sprintf(dateTextFormat, m.nYear, m.nConventionalMonth, m.nDay, m.nHour, m.nMinute, m.nSecond)
+	if m.isLeap {
+		// 闰字放在年和月的分隔符后面, 年份不一定是四位, 也可能是负数
+		nPos := strings.Index(strText[1:], "-") + 2
+		strText = strText[:nPos] + leapMonthTextMark + strText[nPos:]
+	}
+	return []byte(strText), nil
+}
+
+// UnmarshalText 文本反序列化, 格式同 MarshalText
+func (m *TLunarDate) UnmarshalText(text []byte) error {
+	strText := string(text)
+	isLeap := strings.Contains(strText, leapMonthTextMark)
+	strText = strings.Replace(strText, leapMonthTextMark, "", 1)
+
+	var nYear, nMonth, nDay, nHour, nMinute, nSecond int
+	if _, err := fmt.Sscanf(strText, "%d-%d-%d %d:%d:%d", &nYear, &nMonth, &nDay, &nHour, &nMinute, &nSecond); err != nil {
+		return fmt.Errorf("无效的农历时间 %q: %v", text, err)
+	}
+
+	pDate := NewLunarDateFromLeap(nYear, nMonth, nDay, nHour, nMinute, nSecond, isLeap)
+	if pDate == nil || !pDate.GetTimeIsValid(nHour, nMinute, nSecond) {
+		return fmt.Errorf("无效的农历时间 %q", text)
+	}
+	// 闰月标记必须和当年的闰月对得上
+	if isLeap && pDate.nLeapMonth != nMonth {
+		return fmt.Errorf("无效的农历时间 %q: 当年没有闰%d月", text, nMonth)
+	}
+	*m = *pDate
+	return nil
+}
+
+// MarshalJSON JSON 序列化, 输出 MarshalText 的字符串
+func (m *TLunarDate) MarshalJSON() ([]byte, error) {
+	text, _ := m.MarshalText()
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON JSON 反序列化
+func (m *TLunarDate) UnmarshalJSON(data []byte) error {
+	var strText string
+	if err := json.Unmarshal(data, &strText); err != nil {
+		return err
+	}
+	return m.UnmarshalText([]byte(strText))
+}
@@ -0,0 +1,170 @@
+package bazi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestSolarDateTime 新历和 time.Time 互转, 按钟面时间
+func TestSolarDateTime(t *testing.T) {
+	// Unix 原点就是 unixEpoch64TimeStamp
+	pEpoch := NewSolarDateFromTime(time.Unix(0, 0).UTC())
+	if pEpoch.String() != NewSolarDate(1970, 1, 1, 0, 0, 0).String() || pEpoch.Get64TimeStamp() != unixEpoch64TimeStamp {
+		t.Errorf("Unix 原点 = %v %d, want 1970-01-01 %d", pEpoch, pEpoch.Get64TimeStamp(), unixEpoch64TimeStamp)
+	}
+
+	loc := time.FixedZone("CST", 8*60*60)
+	cases := []struct {
+		tm      time.Time
+		strWant string
+	}{
+		{time.Date(2024, 2, 10, 8, 30, 15, 0, loc), "2024-02-10 08:30:15"},
+		{time.Date(2024, 2, 10, 8, 30, 15, 0, time.UTC), "2024-02-10 08:30:15"},
+		{time.Date(1900, 2, 28, 23, 59, 59, 0, loc), "1900-02-28 23:59:59"},
+		{time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC), "1582-10-15 00:00:00"},
+		{time.Date(1582, 10, 14, 0, 0, 0, 0, time.UTC), "1582-10-04 00:00:00"}, // 格里高利历外推的前一天就是儒略历的10月4日
+		{time.Date(1000, 3, 1, 0, 0, 0, 0, time.UTC), "1000-02-24 00:00:00"},
+	}
+	for _, c := range cases {
+		pDate := new(TSolarDate).FromTime(c.tm)
+		text, _ := pDate.MarshalText()
+		if string(text) != c.strWant {
+			t.Errorf("FromTime(%v) = %s, want %s", c.tm, text, c.strWant)
+		}
+		if got := pDate.ToTime(c.tm.Location()); !got.Equal(c.tm) {
+			t.Errorf("%s ToTime = %v, want %v", text, got, c.tm)
+		}
+	}
+
+	// 空时区用本地时区
+	if got := NewSolarDate(2024, 2, 10, 8, 0, 0).ToTime(nil); got.Location() != time.Local || got.Hour() != 8 {
+		t.Errorf("ToTime(nil) = %v", got)
+	}
+}
+
+// TestSolarDateCompare 比较和加减时长
+func TestSolarDateCompare(t *testing.T) {
+	p1 := NewSolarDate(2024, 2, 28, 18, 0, 0)
+	p2 := NewSolarDate(2024, 3, 1, 6, 0, 0)
+	if !p1.Before(p2) || p1.After(p2) || !p2.After(p1) || p1.Before(p1) || p1.After(p1) {
+		t.Errorf("Before/After %v %v", p1, p2)
+	}
+
+	cases := []struct {
+		d       time.Duration
+		strWant string
+	}{
+		{36 * time.Hour, "2024-03-01 06:00:00"}, // 闰年二月有29日
+		{-time.Second, "2024-02-28 17:59:59"},
+		{1500 * time.Millisecond, "2024-02-28 18:00:01"}, // 不足一秒的忽略
+		{-365 * 24 * time.Hour, "2023-02-28 18:00:00"},
+	}
+	for _, c := range cases {
+		text, _ := p1.Add(c.d).MarshalText()
+		if string(text) != c.strWant {
+			t.Errorf("%v Add(%v) = %s, want %s", p1, c.d, text, c.strWant)
+		}
+	}
+	if text, _ := p1.MarshalText(); string(text) != "2024-02-28 18:00:00" {
+		t.Errorf("Add 改了原来的日期 %s", text)
+	}
+}
+
+// TestSolarDateMarshal 文本和 JSON 序列化
+func TestSolarDateMarshal(t *testing.T) {
+	pDate := NewSolarDate(2024, 2, 10, 8, 30, 15)
+	data, err := json.Marshal(pDate)
+	if err != nil || string(data) != `"2024-02-10 08:30:15"` {
+		t.Fatalf("MarshalJSON = %s %v", data, err)
+	}
+	var pBack TSolarDate
+	if err := json.Unmarshal(data, &pBack); err != nil || pBack != *pDate {
+		t.Errorf("UnmarshalJSON(%s) = %v %v", data, pBack, err)
+	}
+
+	for _, strText := range []string{"2023-02-29 00:00:00", "2024-02-10 24:00:00", "2024-02-10", "abc"} {
+		if err := new(TSolarDate).UnmarshalText([]byte(strText)); err == nil {
+			t.Errorf("UnmarshalText(%q) should fail", strText)
+		}
+	}
+	if err := json.Unmarshal([]byte(`20240210`), new(TSolarDate)); err == nil {
+		t.Errorf("UnmarshalJSON 不是字符串 should fail")
+	}
+}
+
+// TestLunarDateTime 农历和 time.Time 互转, 比较和加减
+func TestLunarDateTime(t *testing.T) {
+	loc := time.FixedZone("CST", 8*60*60)
+	tm := time.Date(2023, 3, 22, 9, 0, 0, 0, loc) // 闰二月初一
+	pDate := NewLunarDateFromTime(tm)
+	if pDate == nil || pDate.Month() != "闰二月" || pDate.nDay != 1 || pDate.nHour != 9 {
+		t.Fatalf("NewLunarDateFromTime(%v) = %v", tm, pDate)
+	}
+	if got := pDate.ToTime(loc); !got.Equal(tm) {
+		t.Errorf("ToTime = %v, want %v", got, tm)
+	}
+
+	// 闰二月小, 29天后是三月初一
+	pNext := pDate.Add(29 * 24 * time.Hour)
+	if pNext.Month() != "三月" || pNext.nDay != 1 || !pDate.Before(pNext) || !pNext.After(pDate) {
+		t.Errorf("Add 29天 = %v", pNext)
+	}
+
+	// 超出农历表范围
+	if NewLunarDateFromTime(time.Date(1700, 1, 1, 0, 0, 0, 0, time.UTC)) != nil {
+		t.Errorf("1700年 should be nil")
+	}
+	pOld := *pDate
+	if pDate.FromTime(time.Date(1700, 1, 1, 0, 0, 0, 0, time.UTC)) != nil || *pDate != pOld {
+		t.Errorf("FromTime 超出范围应该返回 nil 并保持原值, got %v", pDate)
+	}
+}
+
+// TestLunarDateMarshal 农历文本和 JSON 序列化, 闰月加 闰 字
+func TestLunarDateMarshal(t *testing.T) {
+	cases := []struct {
+		nYear, nMonth, nDay int
+		strWant             string
+	}{
+		{2023, 3, 22, "2023-闰02-01 00:00:00"},
+		{2023, 3, 21, "2023-02-30 00:00:00"},
+		{2023, 4, 20, "2023-03-01 00:00:00"},
+		{2033, 12, 22, "2033-闰11-01 00:00:00"},
+		{2024, 2, 10, "2024-01-01 00:00:00"},
+	}
+	for _, c := range cases {
+		pDate := NewSolarDate(c.nYear, c.nMonth, c.nDay, 0, 0, 0).ToLunarDate()
+		data, err := json.Marshal(pDate)
+		if err != nil || string(data) != `"`+c.strWant+`"` {
+			t.Errorf("%d-%d-%d MarshalJSON = %s %v, want %q", c.nYear, c.nMonth, c.nDay, data, err, c.strWant)
+			continue
+		}
+		var pBack TLunarDate
+		if err := json.Unmarshal(data, &pBack); err != nil || pBack.Get64TimeStamp() != pDate.Get64TimeStamp() || pBack.isLeap != pDate.isLeap {
+			t.Errorf("UnmarshalJSON(%s) = %v %v", data, pBack.ToSolarDate(), err)
+		}
+	}
+
+	// 年份不是四位时闰字也要放在月份前面
+	for _, c := range []struct {
+		nYear   int
+		strWant string
+	}{
+		{500, "0500-闰04-01 00:00:00"},
+		{-1000, "-1000-闰04-01 00:00:00"},
+		{12345, "12345-闰04-01 00:00:00"},
+	} {
+		pDate := &TLunarDate{nYear: c.nYear, nConventionalMonth: 4, nDay: 1, isLeap: true}
+		if text, _ := pDate.MarshalText(); string(text) != c.strWant {
+			t.Errorf("%d MarshalText = %s, want %s", c.nYear, text, c.strWant)
+		}
+	}
+
+	// 2024年没有闰月, 2023年闰的是二月
+	for _, strText := range []string{"2024-闰02-01 00:00:00", "2023-闰03-01 00:00:00", "2023-02-31 00:00:00", "abc"} {
+		if err := new(TLunarDate).UnmarshalText([]byte(strText)); err == nil {
+			t.Errorf("UnmarshalText(%q) should fail", strText)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package bazi
+
+// 节日与杂节
+// 农历节日按农历月日来定, 比如 正月初一春节, 八月十五中秋; 除夕是腊月最后一天, 腊月有大小, 所以要看次日是不是正月初一.
+// 节气直接取节气表.
+// 杂节要结合节气和日干支来定:
+// 寒食   古时是冬至后一百零五日, 现在通行清明前一日
+// 三伏   夏至后第三个庚日初伏, 第四个庚日中伏, 立秋后第一个庚日末伏, 末伏十日后出伏
+// 数九   冬至起一九, 每九天一九, 九九之后出九
+// 入梅   芒种后第一个丙日入梅, 小暑后第一个未日出梅
+// 社日   立春后第五个戊日春社, 立秋后第五个戊日秋社
+// 以上 "后第N个" 都从节气当天算起, 节气当天正好是该干支也算第一个.
+
+// 节日类型
+const (
+	FestivalLunar      = iota // 农历节日
+	FestivalJieQi             // 节气
+	FestivalObservance        // 杂节
+)
+
+// GetFestivalTypeFromNumber 从数字获得节日类型名
+func GetFestivalTypeFromNumber(nValue int) string {
+	switch nValue {
+	case FestivalLunar:
+		return "节日"
+	case FestivalJieQi:
+		return "节气"
+	case FestivalObservance:
+		return "杂节"
+	}
+	return ""
+}
+
+// 农历节日表, 传统月 日 名称
+var lunarfestivallist = []struct {
+	nMonth int
+	nDay   int
+	name   string
+}{
+	{1, 1, "春节"},
+	{1, 15, "元宵"},
+	{2, 2, "龙抬头"},
+	{3, 3, "上巳"},
+	{5, 5, "端午"},
+	{7, 7, "七夕"},
+	{7, 15, "中元"},
+	{8, 15, "中秋"},
+	{9, 9, "重阳"},
+	{10, 1, "寒衣"},
+	{10, 15, "下元"},
+	{12, 8, "腊八"},
+	{12, 23, "小年"},
+}
+
+// TFestival 节日
+type TFestival struct {
+	strName    string      // 名称
+	nType      int         // 类型
+	pSolarDate *TSolarDate // 新历日期
+}
+
+// NewFestival 新建节日
+func NewFestival(strName string, nType int, pSolarDate *TSolarDate) *TFestival {
+	return &TFestival{
+		strName:    strName,
+		nType:      nType,
+		pSolarDate: pSolarDate,
+	}
+}
+
+// Name 名称
+func (m *TFestival) Name() string {
+	return m.strName
+}
+
+// Type 类型
+func (m *TFestival) Type() int {
+	return m.nType
+}
+
+// TypeName 类型名
+func (m *TFestival) TypeName() string {
+	return GetFestivalTypeFromNumber(m.nType)
+}
+
+// Date 新历日期
+func (m *TFestival) Date() *TSolarDate {
+	return m.pSolarDate
+}
+
+// String 打印用
+func (m *TFestival) String() string {
+	return m.strName
+}
+
+// GetFestivals 获取某个新历日期当天的节日, 节气和杂节
+func GetFestivals(pSolarDate *TSolarDate) []*TFestival {
+	var result []*TFestival
+	// 只看日期, 时间归零
+	pDate := NewSolarDate(pSolarDate.Year(), pSolarDate.Month(), pSolarDate.Day(), 0, 0, 0)
+	if pDate == nil {
+		return result
+	}
+
+	for _, strName := range getLunarFestivals(pDate) {
+		result = append(result, NewFestival(strName, FestivalLunar, pDate))
+	}
+	for _, strName := range getJieQiFestivals(pDate) {
+		result = append(result, NewFestival(strName, FestivalJieQi, pDate))
+	}
+	for _, strName := range getObservanceFestivals(pDate) {
+		result = append(result, NewFestival(strName, FestivalObservance, pDate))
+	}
+	return result
+}
+
+// GetFestivalList 获取某个新历年全年的节日, 按日期排序
+func GetFestivalList(nYear int) []*TFestival {
+	var result []*TFestival
+	pStart := NewSolarDate(nYear, 1, 1, 0, 0, 0)
+	pEnd := NewSolarDate(nYear, 12, 31, 0, 0, 0)
+	if pStart == nil || pEnd == nil {
+		return result
+	}
+
+	for nJDN := pStart.JulianDayNumber(); nJDN <= pEnd.JulianDayNumber(); nJDN++ {
+		result = append(result, GetFestivals(newSolarDateFromJulianDayNumber(nJDN))...)
+	}
+	return result
+}
+
+// newSolarDateFromJulianDayNumber 儒略日数当天零点
+func newSolarDateFromJulianDayNumber(nJDN int) *TSolarDate {
+	return NewSolarDateFrom64TimeStamp(int64(nJDN-jdnOfAllDaysOrigin) * 24 * 60 * 60)
+}
+
+// getLunarFestivals 农历节日
+func getLunarFestivals(pDate *TSolarDate) []string {
+	var result []string
+	pLunarDate := pDate.ToLunarDate()
+	if pLunarDate == nil {
+		return result
+	}
+
+	if !pLunarDate.isLeap {
+		for _, festival := range lunarfestivallist {
+			if pLunarDate.nConventionalMonth == festival.nMonth && pLunarDate.nDay == festival.nDay {
+				result = append(result, festival.name)
+			}
+		}
+	}
+
+	// 除夕, 次日是正月初一
+	pNextDate := NewLunarDateFrom64TimeStamp(pDate.Get64TimeStamp() + 24*60*60)
+	if pNextDate != nil && !pNextDate.isLeap && pNextDate.nConventionalMonth == 1 && pNextDate.nDay == 1 {
+		result = append(result, "除夕")
+	}
+	return result
+}
+
+// getJieQiFestivals 当天的节气
+func getJieQiFestivals(pDate *TSolarDate) []string {
+	var result []string
+	for nJieQi := 0; nJieQi < 24; nJieQi++ {
+		if getJieQiDayOfYear(pDate.Year(), nJieQi) == pDate.JulianDayNumber() {
+			result = append(result, GetJieQiFromNumber(nJieQi))
+		}
+	}
+	return result
+}
+
+// getObservanceFestivals 杂节
+func getObservanceFestivals(pDate *TSolarDate) []string {
+	var result []string
+	nYear := pDate.Year()
+	nJDN := pDate.JulianDayNumber()
+
+	add := func(nDay int, strName string) {
+		if nDay > 0 && nDay == nJDN {
+			result = append(result, strName)
+		}
+	}
+
+	// 寒食, 清明前一日
+	if nQingMing := getJieQiDayOfYear(nYear, 4); nQingMing > 0 {
+		add(nQingMing-1, "寒食")
+	}
+
+	// 社日
+	add(getNthGanDay(getJieQiDayOfYear(nYear, 0), 4, 5), "春社")
+	add(getNthGanDay(getJieQiDayOfYear(nYear, 12), 4, 5), "秋社")
+
+	// 入梅 出梅
+	add(getNthGanDay(getJieQiDayOfYear(nYear, 8), 2, 1), "入梅")
+	add(getNthZhiDay(getJieQiDayOfYear(nYear, 10), 7, 1), "出梅")
+
+	// 三伏
+	nXiaZhi := getJieQiDayOfYear(nYear, 9)
+	nMoFu := getNthGanDay(getJieQiDayOfYear(nYear, 12), 6, 1)
+	add(getNthGanDay(nXiaZhi, 6, 3), "初伏")
+	add(getNthGanDay(nXiaZhi, 6, 4), "中伏")
+	add(nMoFu, "末伏")
+	if nMoFu > 0 {
+		add(nMoFu+10, "出伏")
+	}
+
+	// 数九, 上一年冬至一直数到今年春天, 今年冬至又开始
+	for _, nDongZhi := range []int{getJieQiDayOfYear(nYear-1, 21), getJieQiDayOfYear(nYear, 21)} {
+		if nDongZhi <= 0 {
+			continue
+		}
+		for i := 0; i < 9; i++ {
+			add(nDongZhi+9*i, GetChnChar(i+1)+"九")
+		}
+		add(nDongZhi+81, "出九")
+	}
+	return result
+}
+
+// getJieQiDateOfYear 获取某个新历年的某个节气, 没有的话返回 nil
+func getJieQiDateOfYear(nYear int, nJieQi int) *TJieQiDate {
+	// 节气表从31年的小寒开始, 每年24个, 顺序是 小寒 大寒 立春 ... 冬至
+	nIndex := (nYear-31)*24 + (nJieQi+2)%24
+	if nIndex < 0 || nIndex >= len(jieqilist) {
+		return nil
+	}
+	pJieQiDate := jieqilist[nIndex]
+	if pJieQiDate.Year != nYear || pJieQiDate.JieQi.Value() != nJieQi {
+		return nil
+	}
+	return pJieQiDate
+}
+
+// getJieQiDayOfYear 获取某个新历年的某个节气当天的儒略日数, 没有的话返回0
+func getJieQiDayOfYear(nYear int, nJieQi int) int {
+	pJieQiDate := getJieQiDateOfYear(nYear, nJieQi)
+	if pJieQiDate == nil {
+		return 0
+	}
+	return GetJulianDayNumber(pJieQiDate.Year, pJieQiDate.Month, pJieQiDate.Day)
+}
+
+// getNthGanDay 从某天(含当天)起第N个天干为 nGan 的日子, 起始日无效时返回0
+func getNthGanDay(nStartJDN int, nGan int, nTh int) int {
+	if nStartJDN <= 0 {
+		return 0
+	}
+	nStartGan := NewGanZhiFromJulianDay(nStartJDN).Value() % 10
+	return nStartJDN + (nGan-nStartGan+10)%10 + 10*(nTh-1)
+}
+
+// getNthZhiDay 从某天(含当天)起第N个地支为 nZhi 的日子, 起始日无效时返回0
+func getNthZhiDay(nStartJDN int, nZhi int, nTh int) int {
+	if nStartJDN <= 0 {
+		return 0
+	}
+	nStartZhi := NewGanZhiFromJulianDay(nStartJDN).Value() % 12
+	return nStartJDN + (nZhi-nStartZhi+12)%12 + 12*(nTh-1)
+}
@@ -0,0 +1,96 @@
+package bazi
+
+import "testing"
+
+// hasFestival 当天有没有这个节日
+func hasFestival(nYear, nMonth, nDay int, strName string) bool {
+	for _, pFestival := range GetFestivals(NewSolarDate(nYear, nMonth, nDay, 0, 0, 0)) {
+		if pFestival.Name() == strName {
+			return true
+		}
+	}
+	return false
+}
+
+// TestGetFestivals 和万年历对过的日期
+func TestGetFestivals(t *testing.T) {
+	cases := []struct {
+		nYear, nMonth, nDay int
+		strName             string
+	}{
+		// 农历节日
+		{2023, 1, 21, "除夕"},
+		{2023, 1, 22, "春节"},
+		{2023, 9, 29, "中秋"},
+		{1990, 5, 28, "端午"}, // 闰五月之前的五月
+		{2033, 11, 6, "下元"}, // 闰十一月之前的十月
+		{2024, 2, 9, "除夕"},  // 腊月小, 二十九就是除夕
+		{2024, 2, 10, "春节"},
+		{2024, 2, 24, "元宵"},
+		{2024, 6, 10, "端午"},
+		{2024, 9, 17, "中秋"},
+		{2024, 10, 11, "重阳"},
+		// 节气
+		{2024, 2, 4, "立春"},
+		{2024, 4, 4, "清明"},
+		{2024, 12, 21, "冬至"},
+		// 杂节
+		{2024, 4, 3, "寒食"},
+		{2024, 7, 15, "初伏"},
+		{2024, 7, 25, "中伏"},
+		{2024, 8, 14, "末伏"},
+		{2024, 8, 24, "出伏"},
+		{2024, 12, 21, "一九"},
+		{2025, 3, 12, "出九"},
+	}
+	for _, c := range cases {
+		if !hasFestival(c.nYear, c.nMonth, c.nDay, c.strName) {
+			t.Errorf("%d-%d-%d should be %s, got %v", c.nYear, c.nMonth, c.nDay, c.strName, GetFestivals(NewSolarDate(c.nYear, c.nMonth, c.nDay, 0, 0, 0)))
+		}
+	}
+}
+
+// TestLunarLeapMonth 闰月和它前面那个月
+func TestLunarLeapMonth(t *testing.T) {
+	cases := []struct {
+		nYear, nMonth, nDay int
+		strMonth            string
+	}{
+		{1990, 5, 28, "五月"},
+		{1990, 6, 23, "闰五月"},
+		{1990, 7, 22, "六月"},
+		{2023, 3, 22, "闰二月"},
+		{2023, 4, 20, "三月"},
+		{2033, 11, 22, "冬月"},
+		{2033, 12, 22, "闰冬月"},
+	}
+	for _, c := range cases {
+		if strMonth := NewSolarDate(c.nYear, c.nMonth, c.nDay, 0, 0, 0).ToLunarDate().Month(); strMonth != c.strMonth {
+			t.Errorf("%d-%d-%d lunar month = %s, want %s", c.nYear, c.nMonth, c.nDay, strMonth, c.strMonth)
+		}
+	}
+}
+
+// TestGetFestivalList 全年每个农历节日只出现一次, 日期按顺序
+func TestGetFestivalList(t *testing.T) {
+	for _, nYear := range []int{1990, 2024, 2033} {
+		countList := map[string]int{}
+		nLast := 0
+		for _, pFestival := range GetFestivalList(nYear) {
+			if pFestival.Type() == FestivalLunar {
+				countList[pFestival.Name()]++
+			}
+			nJDN := pFestival.Date().JulianDayNumber()
+			if nJDN < nLast {
+				t.Errorf("%d %s out of order", nYear, pFestival.Name())
+			}
+			nLast = nJDN
+		}
+		for _, festival := range lunarfestivallist {
+			// 新历一年里腊月的节日可能出现0次或2次, 只查正月到十月的
+			if festival.nMonth <= 10 && countList[festival.name] != 1 {
+				t.Errorf("%d %s appears %d times", nYear, festival.name, countList[festival.name])
+			}
+		}
+	}
+}
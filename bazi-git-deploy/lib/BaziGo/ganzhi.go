@@ -147,8 +147,15 @@ func NewGanZhiFromYear(nYear int) *TGanZhi {
 }
 
 // NewGanZhiFromDay 获得八字天的干支, 0-59 对应 甲子到癸亥
+// nAllDays 是距公元原点的日数, 即 TSolarDate.GetAllDays()
 func NewGanZhiFromDay(nAllDays int) *TGanZhi {
-	return NewGanZhi(nAllDays + 12)
+	return NewGanZhiFromJulianDay(nAllDays + jdnOfAllDaysOrigin)
+}
+
+// NewGanZhiFromJulianDay 从儒略日数获得日的干支, 0-59 对应 甲子到癸亥
+// 儒略日数 0 是癸丑日(49), 公元前的日子同样适用
+func NewGanZhiFromJulianDay(nJDN int) *TGanZhi {
+	return NewGanZhi((nJDN%60 + 60 + 49) % 60)
 }
 
 // CombineGanZhi 将天干地支组合成干支，0-9 0-11 转换成 0-59
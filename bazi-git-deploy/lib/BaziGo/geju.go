@@ -0,0 +1,422 @@
+package bazi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 格局
+// 格局从月令取, 月支是提纲, 先看月支藏干哪个透出天干, 透出的就是格.
+// 月令本气是日主的禄, 为建禄格; 阳干月令是刃, 为月刃格.
+// 都不透就取本气, 本气是比劫的取中气余气.
+// 取了格再看成败, 比如 正官格见伤官为破, 有印护官又能成.
+// 特殊格局先于正格判断:
+// 化气格   日干与月干或时干五合, 月令是化神五行, 天干没有克化神的字
+// 专旺格   日主五行得令又占大半, 天干不见官杀
+// 从格     日主无根, 天干不见印比, 弃命从最旺的财 官杀 或 食伤
+
+// 格局类别
+const (
+	GeJuZheng   = iota // 正格
+	GeJuSpecial        // 特殊格局
+)
+
+// GetGeJuCategoryFromNumber 从数字获得格局类别名
+func GetGeJuCategoryFromNumber(nValue int) string {
+	switch nValue {
+	case GeJuZheng:
+		return "正格"
+	case GeJuSpecial:
+		return "特殊格局"
+	}
+	return ""
+}
+
+// 十神对应的正格名, 比劫按建禄月刃另算
+var gejunamelist = [10]string{"", "", "食神格", "伤官格", "财格", "财格", "七杀格", "正官格", "印格", "印格"}
+
+// 专旺格按五行的名字 金木水火土
+var zhuanwangnamelist = [5]string{"从革格", "曲直格", "润下格", "炎上格", "稼穑格"}
+
+// 日干的禄 甲禄寅 乙禄卯 丙戊禄巳 丁己禄午 庚禄申 辛禄酉 壬禄亥 癸禄子
+var ganlulist = [10]int{2, 3, 5, 6, 5, 6, 8, 9, 11, 0}
+
+// 阳干的刃 甲刃卯 丙戊刃午 庚刃酉 壬刃子, 阴干不论月刃
+var ganrenlist = [10]int{3, -1, 6, -1, 6, -1, 9, -1, 0, -1}
+
+// 专旺格日主五行至少占的比例(百分比)
+const zhuanWangPercent = 50
+
+// 从格日主和印星合计不超过的比例(百分比)
+const congGePercent = 20
+
+// NewGeJu 新建格局
+func NewGeJu(pSiZhu *TSiZhu) *TGeJu {
+	p := &TGeJu{
+		pSiZhu: pSiZhu,
+	}
+	p.init()
+	return p
+}
+
+// TGeJu 格局
+type TGeJu struct {
+	pSiZhu       *TSiZhu
+	strName      string    // 格局名
+	nCategory    int       // 类别
+	pShiShen     *TShiShen // 取格的十神, 特殊格局为空
+	isCheng      bool      // 成格还是破格
+	evidenceList []string  // 判断依据
+}
+
+func (m *TGeJu) init() *TGeJu {
+	if m.checkHuaQi() || m.checkZhuanWang() || m.checkCongGe() {
+		m.nCategory = GeJuSpecial
+		m.isCheng = true
+		return m
+	}
+
+	m.nCategory = GeJuZheng
+	m.checkZhengGe()
+	m.checkChengBai()
+	return m
+}
+
+// addEvidence 添加一条依据
+func (m *TGeJu) addEvidence(strFormat string, args ...interface{}) {
+	m.evidenceList = append(m.evidenceList, fmt.Sprintf(strFormat, args...))
+}
+
+// ganList 年干 月干 时干, 日干是自己不算
+func (m *TGeJu) ganList() []*TGan {
+	return []*TGan{m.pSiZhu.YearZhu().Gan(), m.pSiZhu.MonthZhu().Gan(), m.pSiZhu.HourZhu().Gan()}
+}
+
+// zhiList 四个地支
+func (m *TGeJu) zhiList() []*TZhi {
+	return []*TZhi{m.pSiZhu.YearZhu().Zhi(), m.pSiZhu.MonthZhu().Zhi(), m.pSiZhu.DayZhu().Zhi(), m.pSiZhu.HourZhu().Zhi()}
+}
+
+// isTouGan 某个天干是否透出在年干 月干 时干
+func (m *TGeJu) isTouGan(nGan int) bool {
+	for _, pGan := range m.ganList() {
+		if pGan.Value() == nGan {
+			return true
+		}
+	}
+	return false
+}
+
+// touShiShen 透出天干里的某些十神, 返回第一个找到的十神名, 没有返回空
+func (m *TGeJu) touShiShen(shishenList ...int) string {
+	nDayGan := m.pSiZhu.DayZhu().Gan().Value()
+	for _, pGan := range m.ganList() {
+		nShiShen := NewShiShenFromGan(nDayGan, pGan).Value()
+		for _, n := range shishenList {
+			if nShiShen == n {
+				return GetShiShenLongFromNumber(nShiShen)
+			}
+		}
+	}
+	return ""
+}
+
+// percent 某些五行占总强度的百分比
+func (m *TGeJu) percent(wuxingList ...int) int {
+	strengthList := m.pSiZhu.XiYong().WuXingList()
+	nTotal, nPart := 0, 0
+	for _, n := range strengthList {
+		nTotal += n
+	}
+	for _, n := range wuxingList {
+		nPart += strengthList[n]
+	}
+	if nTotal == 0 {
+		return 0
+	}
+	return nPart * 100 / nTotal
+}
+
+// checkHuaQi 化气格, 日干和紧贴的月干 时干合化成功
+func (m *TGeJu) checkHuaQi() bool {
+	for _, pHeHua := range m.pSiZhu.HeHuaList() {
+		if !pHeHua.Has("日干") || !pHeHua.IsHua() {
+			continue
+		}
+
+		m.strName = "化气格"
+		for _, strReason := range pHeHua.Reasons() {
+			m.addEvidence("%s", strReason)
+		}
+		return true
+	}
+	return false
+}
+
+// checkZhuanWang 专旺格
+func (m *TGeJu) checkZhuanWang() bool {
+	pDayGan := m.pSiZhu.DayZhu().Gan()
+	nDayWuXing := pDayGan.ToWuXing().Value()
+
+	if m.pSiZhu.MonthZhu().Zhi().ToWuXing().Value() != nDayWuXing {
+		return false
+	}
+	nPercent := m.percent(nDayWuXing)
+	if nPercent < zhuanWangPercent {
+		return false
+	}
+	if m.touShiShen(6, 7) != "" {
+		return false
+	}
+
+	m.strName = "专旺格"
+	m.addEvidence("%s, 日主%s生于%s月得令", zhuanwangnamelist[nDayWuXing], pDayGan, m.pSiZhu.MonthZhu().Zhi())
+	m.addEvidence("%s占五行强度%d%%", GetWuXingFromNumber(nDayWuXing), nPercent)
+	m.addEvidence("天干不见官杀")
+	return true
+}
+
+// checkCongGe 从格
+func (m *TGeJu) checkCongGe() bool {
+	pDayGan := m.pSiZhu.DayZhu().Gan()
+	pDayWuXing := pDayGan.ToWuXing()
+	nDayWuXing := pDayWuXing.Value()
+	nYinWuXing := pDayWuXing.Mother().Value()
+
+	// 地支藏干有日主同类就是有根
+	for _, pZhi := range m.zhiList() {
+		for _, nGan := range cangganlist[pZhi.Value()] {
+			if nGan >= 0 && NewGan(nGan).ToWuXing().Value() == nDayWuXing {
+				return false
+			}
+		}
+	}
+	if m.touShiShen(0, 1, 8, 9) != "" {
+		return false
+	}
+	nPercent := m.percent(nDayWuXing, nYinWuXing)
+	if nPercent > congGePercent {
+		return false
+	}
+
+	// 从最旺的一方
+	strengthList := m.pSiZhu.XiYong().WuXingList()
+	nCong := -1
+	for i, n := range strengthList {
+		if i == nDayWuXing || i == nYinWuXing {
+			continue
+		}
+		if nCong < 0 || n > strengthList[nCong] {
+			nCong = i
+		}
+	}
+
+	switch {
+	case pDayWuXing.Controls(NewWuXing(nCong)):
+		m.strName = "从财格"
+	case pDayWuXing.ControlledBy(NewWuXing(nCong)):
+		m.strName = "从杀格"
+	default:
+		m.strName = "从儿格"
+	}
+	m.addEvidence("日主%s在地支无根, 天干不见印比", pDayGan)
+	m.addEvidence("日主和印星只占五行强度%d%%", nPercent)
+	m.addEvidence("%s最旺, 弃命相从", GetWuXingFromNumber(nCong))
+	return true
+}
+
+// checkZhengGe 正格, 从月令取格
+func (m *TGeJu) checkZhengGe() {
+	nDayGan := m.pSiZhu.DayZhu().Gan().Value()
+	pMonthZhi := m.pSiZhu.MonthZhu().Zhi()
+	pCangGan := m.pSiZhu.MonthZhu().CangGan()
+
+	// 建禄 月刃
+	if ganlulist[nDayGan] == pMonthZhi.Value() {
+		m.strName = "建禄格"
+		m.pShiShen = pCangGan.ShiShen(0)
+		m.addEvidence("月令%s是日主%s的禄", pMonthZhi, NewGan(nDayGan))
+		return
+	}
+	if ganrenlist[nDayGan] == pMonthZhi.Value() {
+		m.strName = "月刃格"
+		m.pShiShen = pCangGan.ShiShen(0)
+		m.addEvidence("月令%s是日主%s的羊刃", pMonthZhi, NewGan(nDayGan))
+		return
+	}
+
+	// 藏干按本气 中气 余气的顺序, 先取透干的
+	nIndex := -1
+	for i := 0; i < pCangGan.Size(); i++ {
+		if isShiShenBiJie(pCangGan.ShiShen(i).Value()) {
+			continue
+		}
+		if m.isTouGan(pCangGan.Gan(i).Value()) {
+			nIndex = i
+			m.addEvidence("月令%s藏%s透出天干", pMonthZhi, pCangGan.Gan(i))
+			break
+		}
+	}
+
+	// 都不透就取本气, 本气是比劫就往后取
+	if nIndex < 0 {
+		for i := 0; i < pCangGan.Size(); i++ {
+			if !isShiShenBiJie(pCangGan.ShiShen(i).Value()) {
+				nIndex = i
+				m.addEvidence("月令%s藏干不透, 取%s%s", pMonthZhi, pCangGan.RoleName(i), pCangGan.Gan(i))
+				break
+			}
+		}
+	}
+
+	// 月令只有比劫, 按建禄论
+	if nIndex < 0 {
+		m.strName = "建禄格"
+		m.pShiShen = pCangGan.ShiShen(0)
+		m.addEvidence("月令%s只藏比劫", pMonthZhi)
+		return
+	}
+
+	m.pShiShen = pCangGan.ShiShen(nIndex)
+	m.strName = gejunamelist[m.pShiShen.Value()]
+	m.addEvidence("%s为%s", pCangGan.Gan(nIndex), GetShiShenLongFromNumber(m.pShiShen.Value()))
+}
+
+// isShiShenBiJie 是否比肩劫财
+func isShiShenBiJie(nShiShen int) bool {
+	return nShiShen == 0 || nShiShen == 1
+}
+
+// checkChengBai 正格的成败
+func (m *TGeJu) checkChengBai() {
+	// 身强身弱, 日主和印星占一半算强
+	pDayWuXing := m.pSiZhu.DayZhu().Gan().ToWuXing()
+	nPercent := m.percent(pDayWuXing.Value(), pDayWuXing.Mother().Value())
+	isStrong := nPercent >= 50
+	if isStrong {
+		m.addEvidence("日主和印星占五行强度%d%%, 身强", nPercent)
+	} else {
+		m.addEvidence("日主和印星占五行强度%d%%, 身弱", nPercent)
+	}
+
+	strShang := m.touShiShen(3)
+	strGuan := m.touShiShen(7)
+	strSha := m.touShiShen(6)
+	strGuanSha := m.touShiShen(6, 7)
+	strCai := m.touShiShen(4, 5)
+	strYin := m.touShiShen(8, 9)
+	strShiShang := m.touShiShen(2, 3)
+	strBiJie := m.touShiShen(0, 1)
+	strShiYin := m.touShiShen(2, 8, 9) // 食神制杀 或 印化杀
+
+	// 破格的理由, 有救的话再成
+	strBroken, strRescue := "", ""
+	switch m.strName {
+	case "正官格":
+		switch {
+		case strShang != "":
+			strBroken, strRescue = "伤官见官", strYin
+		case strSha != "":
+			strBroken = "官杀混杂"
+		}
+	case "七杀格":
+		if strCai != "" && strShiYin == "" {
+			strBroken = "财党杀, 无食制无印化"
+		} else if strShiYin == "" && !isStrong {
+			strBroken = "七杀无制, 身弱难当"
+		}
+	case "财格":
+		if strBiJie != "" {
+			strBroken, strRescue = "比劫夺财", strGuanSha
+		} else if !isStrong {
+			strBroken, strRescue = "财多身弱", strYin
+		}
+	case "印格":
+		if strCai != "" {
+			strBroken, strRescue = "贪财坏印", strBiJie
+		}
+	case "食神格":
+		if m.touShiShen(8) != "" {
+			strBroken, strRescue = "枭神夺食", strCai
+		}
+	case "伤官格":
+		if strGuan != "" {
+			strBroken, strRescue = "伤官见官", strYin
+		}
+	case "建禄格":
+		if strGuanSha == "" && strCai == "" && strShiShang == "" {
+			strBroken = "禄格不见财官食伤, 无所用"
+		}
+	case "月刃格":
+		if strGuanSha == "" {
+			strBroken = "月刃不见官杀制伏"
+		}
+	}
+
+	switch {
+	case strBroken == "":
+		m.isCheng = true
+		m.addEvidence("%s无破", m.strName)
+	case strRescue != "":
+		m.isCheng = true
+		m.addEvidence("%s, 有%s透出相救", strBroken, strRescue)
+	default:
+		m.isCheng = false
+		m.addEvidence("%s, 破格", strBroken)
+	}
+}
+
+// Name 格局名
+func (m *TGeJu) Name() string {
+	return m.strName
+}
+
+// Category 类别 GeJuZheng GeJuSpecial
+func (m *TGeJu) Category() int {
+	return m.nCategory
+}
+
+// CategoryName 类别名
+func (m *TGeJu) CategoryName() string {
+	return GetGeJuCategoryFromNumber(m.nCategory)
+}
+
+// ShiShen 取格的十神, 特殊格局为 nil
+func (m *TGeJu) ShiShen() *TShiShen {
+	return m.pShiShen
+}
+
+// IsCheng 是否成格
+func (m *TGeJu) IsCheng() bool {
+	return m.isCheng
+}
+
+// Status 成格 或者 破格
+func (m *TGeJu) Status() string {
+	if m.isCheng {
+		return "成格"
+	}
+	return "破格"
+}
+
+// Evidence 判断依据
+func (m *TGeJu) Evidence() []string {
+	return m.evidenceList
+}
+
+// String 打印用
+func (m *TGeJu) String() string {
+	return fmt.Sprintf("格局: %s(%s) %s\n%s", m.strName, m.CategoryName(), m.Status(), strings.Join(m.evidenceList, "\n"))
+}
+
+// MarshalJSON JSON 序列化
+func (m *TGeJu) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name     string   `json:"name"`
+		Category string   `json:"category"`
+		Status   string   `json:"status"`
+		IsCheng  bool     `json:"isCheng"`
+		Evidence []string `json:"evidence"`
+	}{m.strName, m.CategoryName(), m.Status(), m.isCheng, m.evidenceList})
+}
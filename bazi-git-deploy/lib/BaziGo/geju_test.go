@@ -0,0 +1,50 @@
+package bazi
+
+import "testing"
+
+// TestGeJu 各种格局, 都取中午12点
+func TestGeJu(t *testing.T) {
+	cases := []struct {
+		nYear, nMonth, nDay int
+		strName             string
+		nCategory           int
+		strStatus           string
+		strLast             string // 最后一条依据
+	}{
+		// 庚寅 辛巳 癸卯 戊午, 日干癸和时干戊合, 巳月火当令
+		{1950, 5, 8, "化气格", GeJuSpecial, "成格", "天干不见克火之字"},
+		// 己丑 丁丑 戊申 戊午, 戊土生丑月, 土占一半, 不见甲乙
+		{1950, 1, 13, "专旺格", GeJuSpecial, "成格", "天干不见官杀"},
+		// 壬辰 壬寅 辛卯 甲午, 辛金地支无根, 木最旺是辛的财
+		{1952, 2, 15, "从财格", GeJuSpecial, "成格", "木最旺, 弃命相从"},
+		// 庚寅 戊寅 甲戌 庚午, 甲禄在寅, 庚杀戊财透出
+		{1950, 2, 8, "建禄格", GeJuZheng, "成格", "建禄格无破"},
+		// 壬辰 癸卯 乙卯 壬午, 乙禄在卯, 天干只有印
+		{1952, 3, 10, "建禄格", GeJuZheng, "破格", "禄格不见财官食伤, 无所用, 破格"},
+		// 庚寅 己卯 甲辰 庚午, 甲刃在卯, 庚杀透出
+		{1950, 3, 10, "月刃格", GeJuZheng, "成格", "月刃格无破"},
+		// 庚寅 壬午 戊寅 戊午, 戊刃在午, 天干不见甲乙
+		{1950, 6, 12, "月刃格", GeJuZheng, "破格", "月刃不见官杀制伏, 破格"},
+		// 己丑 丁丑 壬寅 丙午, 丑藏己透出是正官
+		{1950, 1, 7, "正官格", GeJuZheng, "成格", "正官格无破"},
+		// 庚寅 戊寅 己卯 庚午, 寅藏甲不透取本气, 庚是伤官, 不见丙丁
+		{1950, 2, 13, "正官格", GeJuZheng, "破格", "伤官见官, 破格"},
+		// 己丑 丙子 丙申 甲午, 子藏癸是正官, 己伤官透出, 甲偏印相救
+		{1950, 1, 1, "正官格", GeJuZheng, "成格", "伤官见官, 有偏印透出相救"},
+		// 己丑 丁丑 癸卯 戊午, 丑藏己透出是七杀, 丁财透出, 不见乙庚辛
+		{1950, 1, 8, "七杀格", GeJuZheng, "破格", "财党杀, 无食制无印化, 破格"},
+		// 己丑 丙子 戊戌 戊午, 子藏癸是正财, 戊比肩透出
+		{1950, 1, 3, "财格", GeJuZheng, "破格", "比劫夺财, 破格"},
+	}
+	for _, c := range cases {
+		pGeJu := GetBazi(c.nYear, c.nMonth, c.nDay, 12, 0, 0, 1).SiZhu().GeJu()
+		evidenceList := pGeJu.Evidence()
+		if pGeJu.Name() != c.strName || pGeJu.Category() != c.nCategory || pGeJu.Status() != c.strStatus || evidenceList[len(evidenceList)-1] != c.strLast {
+			t.Errorf("%d-%d-%d GeJu = %v, want %s(%s) %s %s", c.nYear, c.nMonth, c.nDay, pGeJu, c.strName, GetGeJuCategoryFromNumber(c.nCategory), c.strStatus, c.strLast)
+		}
+		// 特殊格局没有取格的十神
+		if (pGeJu.ShiShen() == nil) != (c.nCategory == GeJuSpecial) {
+			t.Errorf("%d-%d-%d ShiShen = %v", c.nYear, c.nMonth, c.nDay, pGeJu.ShiShen())
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package bazi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 干支关系图
+// 四柱的干支不是一袋子字, 挨着的年月 月日 日时之间作用力大, 隔一柱的小, 年时最远几乎不论.
+// 天干只和天干论合冲, 地支只和地支论合冲刑害. 每个字是图上的一个点, 两个字之间的关系是一条边,
+// 边上记下两柱的距离和按距离打折后的强度. 行运(大运 流年)的干支和命局哪一柱都算挨着.
+
+// 干支关系的名字
+const (
+	GuanXiWuHe     = "五合" // 天干五合
+	GuanXiGanChong = "相冲" // 天干相冲
+	GuanXiLiuHe    = "六合"
+	GuanXiSanHe    = "三合" // 同一个三合局的两个字, 半合
+	GuanXiLiuChong = "六冲"
+	GuanXiLiuHai   = "六害"
+	GuanXiXing     = "相刑"
+	GuanXiZiXing   = "自刑"
+)
+
+// 第几柱, 命局 0-3 是年月日时
+const (
+	GuanXiDaYun   = 4 // 大运
+	GuanXiLiuNian = 5 // 流年
+)
+
+// TGuanXiDistanceWeightList 关系强度按距离打的折扣(百分比), 下标是两柱的距离 0-3
+type TGuanXiDistanceWeightList [4]int
+
+// 关系强度按距离打的折扣, 同一柱 挨着的不打折, 隔一柱六折, 年时三折
+var guanxidistanceweightlist = TGuanXiDistanceWeightList{100, 100, 60, 30}
+
+// GetGuanXiDistanceWeightList 默认按距离打的折扣
+func GetGuanXiDistanceWeightList() TGuanXiDistanceWeightList {
+	return guanxidistanceweightlist
+}
+
+// TGuanXiTu 干支关系图
+type TGuanXiTu struct {
+	nodeList   []*TGuanXiNode
+	edgeList   []*TGuanXiEdge
+	weightList TGuanXiDistanceWeightList // 按距离打的折扣
+}
+
+// TGuanXiNode 图上的一个字
+type TGuanXiNode struct {
+	nZhu    int    // 第几柱, 0-3 年月日时, 4 大运, 5 流年
+	strFrom string // 年干 年支 ... 大运干 大运支 流年干 流年支
+	pGan    *TGan  // 天干, 地支时为空
+	pZhi    *TZhi  // 地支, 天干时为空
+}
+
+// TGuanXiEdge 两个字之间的一个关系
+type TGuanXiEdge struct {
+	pNode1    *TGuanXiNode
+	pNode2    *TGuanXiNode
+	strName   string // 五合 相冲 六合 三合 六冲 六害 相刑 自刑
+	nDistance int    // 两柱的距离, 行运和命局算1
+	nWeight   int    // 按距离打折后的强度, 百分比
+}
+
+// NewGuanXiTu 命局和行运干支的关系图, pDaYun pLiuNian 为空表示不看, 按默认的折扣打折
+func NewGuanXiTu(pSiZhu *TSiZhu, pDaYun *TGanZhi, pLiuNian *TGanZhi) *TGuanXiTu {
+	return NewGuanXiTuWithWeightList(pSiZhu, pDaYun, pLiuNian, guanxidistanceweightlist)
+}
+
+// NewGuanXiTuWithWeightList 命局和行运干支的关系图, 按传入的折扣打折
+func NewGuanXiTuWithWeightList(pSiZhu *TSiZhu, pDaYun *TGanZhi, pLiuNian *TGanZhi, weightList TGuanXiDistanceWeightList) *TGuanXiTu {
+	p := &TGuanXiTu{weightList: weightList}
+	p.init(pSiZhu, pDaYun, pLiuNian)
+	return p
+}
+
+func (m *TGuanXiTu) init(pSiZhu *TSiZhu, pDaYun *TGanZhi, pLiuNian *TGanZhi) {
+	// 1. 点, 先天干后地支
+	var ganList, zhiList []*TGuanXiNode
+	add := func(nZhu int, strGan, strZhi string, pGanZhi *TGanZhi) {
+		pGan, pZhi := pGanZhi.ExtractGanZhi()
+		ganList = append(ganList, &TGuanXiNode{nZhu: nZhu, strFrom: strGan, pGan: pGan})
+		zhiList = append(zhiList, &TGuanXiNode{nZhu: nZhu, strFrom: strZhi, pZhi: pZhi})
+	}
+	for i, pZhu := range []*TZhu{pSiZhu.YearZhu(), pSiZhu.MonthZhu(), pSiZhu.DayZhu(), pSiZhu.HourZhu()} {
+		add(i, zhuganlist[i], zhuzhilist[i], pZhu.GanZhi())
+	}
+	if pDaYun != nil {
+		add(GuanXiDaYun, "大运干", "大运支", pDaYun)
+	}
+	if pLiuNian != nil {
+		add(GuanXiLiuNian, "流年干", "流年支", pLiuNian)
+	}
+	m.nodeList = append(ganList, zhiList...)
+
+	// 2. 边, 天干和天干, 地支和地支
+	for i := range ganList {
+		for j := i + 1; j < len(ganList); j++ {
+			pGan1, pGan2 := ganList[i].pGan, ganList[j].pGan
+			if pGan1.CombinesWith(pGan2) {
+				m.addEdge(ganList[i], ganList[j], GuanXiWuHe)
+			}
+			if pGan1.Clashes(pGan2) {
+				m.addEdge(ganList[i], ganList[j], GuanXiGanChong)
+			}
+		}
+	}
+	for i := range zhiList {
+		for j := i + 1; j < len(zhiList); j++ {
+			pZhi1, pZhi2 := zhiList[i].pZhi, zhiList[j].pZhi
+			if pZhi1.CombinesWith(pZhi2) {
+				m.addEdge(zhiList[i], zhiList[j], GuanXiLiuHe)
+			}
+			if pZhi1.TrinesWith(pZhi2) {
+				m.addEdge(zhiList[i], zhiList[j], GuanXiSanHe)
+			}
+			if pZhi1.Clashes(pZhi2) {
+				m.addEdge(zhiList[i], zhiList[j], GuanXiLiuChong)
+			}
+			if pZhi1.Harms(pZhi2) {
+				m.addEdge(zhiList[i], zhiList[j], GuanXiLiuHai)
+			}
+			if pZhi1.PunishesEither(pZhi2) {
+				if pZhi1.Value() == pZhi2.Value() {
+					m.addEdge(zhiList[i], zhiList[j], GuanXiZiXing)
+				} else {
+					m.addEdge(zhiList[i], zhiList[j], GuanXiXing)
+				}
+			}
+		}
+	}
+}
+
+// addEdge 添加一条边, 按两柱的距离打折
+func (m *TGuanXiTu) addEdge(pNode1, pNode2 *TGuanXiNode, strName string) {
+	nDistance := guanXiDistance(pNode1.nZhu, pNode2.nZhu)
+	m.edgeList = append(m.edgeList, &TGuanXiEdge{
+		pNode1:    pNode1,
+		pNode2:    pNode2,
+		strName:   strName,
+		nDistance: nDistance,
+		nWeight:   m.weightList[nDistance],
+	})
+}
+
+// guanXiDistance 两柱的距离, 行运和谁都算1
+func guanXiDistance(nZhu1, nZhu2 int) int {
+	if nZhu1 >= GuanXiDaYun || nZhu2 >= GuanXiDaYun {
+		return 1
+	}
+	if nZhu1 > nZhu2 {
+		return nZhu1 - nZhu2
+	}
+	return nZhu2 - nZhu1
+}
+
+// NodeList 所有的点, 先天干后地支, 按年月日时 大运 流年
+func (m *TGuanXiTu) NodeList() []*TGuanXiNode {
+	return m.nodeList
+}
+
+// EdgeList 所有的边
+func (m *TGuanXiTu) EdgeList() []*TGuanXiEdge {
+	return m.edgeList
+}
+
+// EdgeListOf 和某个字有关的边, strFrom 是 年干 日支 流年支 这样的位置
+func (m *TGuanXiTu) EdgeListOf(strFrom string) []*TGuanXiEdge {
+	result := []*TGuanXiEdge{}
+	for _, pEdge := range m.edgeList {
+		if pEdge.pNode1.strFrom == strFrom || pEdge.pNode2.strFrom == strFrom {
+			result = append(result, pEdge)
+		}
+	}
+	return result
+}
+
+// Find 某种关系的所有边, 比如 六冲
+func (m *TGuanXiTu) Find(strName string) []*TGuanXiEdge {
+	result := []*TGuanXiEdge{}
+	for _, pEdge := range m.edgeList {
+		if pEdge.strName == strName {
+			result = append(result, pEdge)
+		}
+	}
+	return result
+}
+
+// Weight 某种关系按距离打折后的强度合计, 比如 六冲 有两处, 一处挨着一处隔一柱, 就是 160
+func (m *TGuanXiTu) Weight(strName string) int {
+	nWeight := 0
+	for _, pEdge := range m.Find(strName) {
+		nWeight += pEdge.nWeight
+	}
+	return nWeight
+}
+
+// MarshalJSON JSON 序列化
+func (m *TGuanXiTu) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Nodes []*TGuanXiNode `json:"nodes"`
+		Edges []*TGuanXiEdge `json:"edges"`
+	}{m.nodeList, m.edgeList})
+}
+
+// Zhu 第几柱, 0-3 年月日时, 4 大运, 5 流年
+func (m *TGuanXiNode) Zhu() int {
+	return m.nZhu
+}
+
+// From 位置, 年干 年支 ... 大运干 大运支 流年干 流年支
+func (m *TGuanXiNode) From() string {
+	return m.strFrom
+}
+
+// IsGan 是不是天干
+func (m *TGuanXiNode) IsGan() bool {
+	return m.pGan != nil
+}
+
+// IsXingYun 是不是行运的字
+func (m *TGuanXiNode) IsXingYun() bool {
+	return m.nZhu >= GuanXiDaYun
+}
+
+// Gan 天干, 地支时为空
+func (m *TGuanXiNode) Gan() *TGan {
+	return m.pGan
+}
+
+// Zhi 地支, 天干时为空
+func (m *TGuanXiNode) Zhi() *TZhi {
+	return m.pZhi
+}
+
+// String 打印用, 比如 日支寅
+func (m *TGuanXiNode) String() string {
+	if m.pGan != nil {
+		return m.strFrom + m.pGan.String()
+	}
+	return m.strFrom + m.pZhi.String()
+}
+
+// MarshalJSON JSON 序列化
+func (m *TGuanXiNode) MarshalJSON() ([]byte, error) {
+	strText, strLayer := "", "支"
+	if m.pGan != nil {
+		strText, strLayer = m.pGan.String(), "干"
+	} else {
+		strText = m.pZhi.String()
+	}
+	return json.Marshal(struct {
+		From  string `json:"from"`
+		Zhu   int    `json:"zhu"`
+		Layer string `json:"layer"`
+		Text  string `json:"text"`
+	}{m.strFrom, m.nZhu, strLayer, strText})
+}
+
+// NodeList 关系两端的字
+func (m *TGuanXiEdge) NodeList() [2]*TGuanXiNode {
+	return [2]*TGuanXiNode{m.pNode1, m.pNode2}
+}
+
+// Name 五合 相冲 六合 三合 六冲 六害 相刑 自刑
+func (m *TGuanXiEdge) Name() string {
+	return m.strName
+}
+
+// Distance 两柱的距离
+func (m *TGuanXiEdge) Distance() int {
+	return m.nDistance
+}
+
+// IsAdjacent 挨着, 或者有行运参与
+func (m *TGuanXiEdge) IsAdjacent() bool {
+	return m.nDistance <= 1
+}
+
+// Weight 按距离打折后的强度, 百分比
+func (m *TGuanXiEdge) Weight() int {
+	return m.nWeight
+}
+
+// String 打印用, 比如 月支寅日支申六冲(距离1, 强度100)
+func (m *TGuanXiEdge) String() string {
+	return fmt.Sprintf("%v%v%s(距离%d, 强度%d)", m.pNode1, m.pNode2, m.strName, m.nDistance, m.nWeight)
+}
+
+// MarshalJSON JSON 序列化
+func (m *TGuanXiEdge) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		From     [2]string `json:"from"`
+		Text     string    `json:"text"`
+		Name     string    `json:"name"`
+		Distance int       `json:"distance"`
+		Adjacent bool      `json:"adjacent"`
+		Weight   int       `json:"weight"`
+	}{[2]string{m.pNode1.strFrom, m.pNode2.strFrom}, m.pNode1.String() + m.pNode2.String() + m.strName, m.strName, m.nDistance, m.IsAdjacent(), m.nWeight})
+}
+
+// GuanXiTu 命局干支的关系图
+func (m *TSiZhu) GuanXiTu() *TGuanXiTu {
+	return m.pGuanXiTu
+}
+
+// GuanXiTu 某一年命局 大运 流年干支的关系图, 大运取这一年所在的那步, 未起运不看大运
+func (m *TBazi) GuanXiTu(nYear int) *TGuanXiTu {
+	var pDaYun *TGanZhi
+	if nIndex := m.DaYunIndexOfYear(nYear); nIndex >= 0 {
+		pDaYun = m.pDaYun.Zhu(nIndex).GanZhi()
+	}
+	return NewGuanXiTu(m.pSiZhu, pDaYun, NewGanZhiFromYear(nYear))
+}
@@ -0,0 +1,29 @@
+package bazi
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestGuanXiTu 庚午 辛巳 壬午 甲辰, 年时庚甲相冲隔两柱, 年日两个午隔一柱
+func TestGuanXiTu(t *testing.T) {
+	pBazi := GetBazi(1990, 5, 17, 8, 0, 0, 1)
+	if got := fmt.Sprint(pBazi.SiZhu().GuanXiTu().EdgeList()); got != "[年干庚时干甲相冲(距离3, 强度30) 年支午日支午自刑(距离2, 强度60)]" {
+		t.Errorf("GuanXiTu = %s", got)
+	}
+
+	// 换一张折扣表只影响这张图, 流年庚子冲两个午, 和辰半合, 行运和谁都算挨着
+	weightList := TGuanXiDistanceWeightList{100, 80, 50, 0}
+	pGuanXiTu := NewGuanXiTuWithWeightList(pBazi.SiZhu(), nil, NewGanZhiFromYear(2020), weightList)
+	want := "[年干庚时干甲相冲(距离3, 强度0) 时干甲流年干庚相冲(距离1, 强度80) 年支午日支午自刑(距离2, 强度50) " +
+		"年支午流年支子六冲(距离1, 强度80) 日支午流年支子六冲(距离1, 强度80) 时支辰流年支子三合(距离1, 强度80)]"
+	if got := fmt.Sprint(pGuanXiTu.EdgeList()); got != want {
+		t.Errorf("NewGuanXiTuWithWeightList = %s, want %s", got, want)
+	}
+	if got := pGuanXiTu.Weight(GuanXiLiuChong); got != 160 {
+		t.Errorf("Weight(六冲) = %d, want 160", got)
+	}
+	if got := NewGuanXiTu(pBazi.SiZhu(), nil, NewGanZhiFromYear(2020)).Weight(GuanXiLiuChong); got != 200 {
+		t.Errorf("默认表 Weight(六冲) = %d, want 200", got)
+	}
+}
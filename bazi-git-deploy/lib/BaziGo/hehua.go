@@ -0,0 +1,241 @@
+package bazi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 天干合化
+// 两干相合要紧贴才合得住, 命局里隔位的是遥合, 合不成; 行运的天干和命局哪一干都算挨着.
+// 一干被两个同样的字来合, 情不专一, 也合不成: 两阳合一阴叫争合, 两阴合一阳叫妒合.
+// 合得住以后, 化神(合化出的五行)当令, 也就是月支五行就是化神, 并且其余天干没有克化神的字, 才算合化, 两干都变成化神的五行.
+// 否则只是合而不化, 两干被合绊住, 五行不变.
+
+// 合化的结果
+const (
+	HeHuaBuCheng = iota // 合不成
+	HeHuaBuHua          // 合而不化
+	HeHuaHua            // 合化
+)
+
+// GetHeHuaResultFromNumber 从数字获得合化结果名, 0-2
+func GetHeHuaResultFromNumber(nValue int) string {
+	switch nValue {
+	case HeHuaBuCheng:
+		return "合不成"
+	case HeHuaBuHua:
+		return "合而不化"
+	case HeHuaHua:
+		return "合化"
+	}
+	return ""
+}
+
+// THeHua 一对相合的天干
+type THeHua struct {
+	ganList    [2]*TGan
+	fromList   [2]string // 年干 月干 日干 时干 大运 流年
+	pHua       *TWuXing  // 化神
+	strName    string    // 甲己合化土
+	nResult    int       // 合不成 合而不化 合化
+	reasonList []string
+}
+
+// heHuaGan 参与合化判断的一个天干, nZhu 是命局的第几柱, 行运为 -1
+type heHuaGan struct {
+	pGan    *TGan
+	strFrom string
+	nZhu    int
+}
+
+// isHeHuaNear 两个天干挨着, 命局要相邻, 行运和谁都算挨着
+func isHeHuaNear(a, b heHuaGan) bool {
+	return a.nZhu < 0 || b.nZhu < 0 || a.nZhu-b.nZhu == 1 || b.nZhu-a.nZhu == 1
+}
+
+// CalcHeHua 命局和行运里所有相合的天干, pDaYun pLiuNian 为空表示不看
+func CalcHeHua(pSiZhu *TSiZhu, pDaYun *TGanZhi, pLiuNian *TGanZhi) []*THeHua {
+	var ganList []heHuaGan
+	for i, pZhu := range []*TZhu{pSiZhu.YearZhu(), pSiZhu.MonthZhu(), pSiZhu.DayZhu(), pSiZhu.HourZhu()} {
+		ganList = append(ganList, heHuaGan{pZhu.Gan(), zhuganlist[i], i})
+	}
+	if pDaYun != nil {
+		pGan, _ := pDaYun.ExtractGanZhi()
+		ganList = append(ganList, heHuaGan{pGan, "大运", -1})
+	}
+	if pLiuNian != nil {
+		pGan, _ := pLiuNian.ExtractGanZhi()
+		ganList = append(ganList, heHuaGan{pGan, "流年", -1})
+	}
+
+	result := []*THeHua{}
+	for i := 0; i < len(ganList); i++ {
+		for j := i + 1; j < len(ganList); j++ {
+			if ganList[i].pGan.CombinesWith(ganList[j].pGan) {
+				result = append(result, newHeHua(pSiZhu.MonthZhu().Zhi(), ganList, i, j))
+			}
+		}
+	}
+	return result
+}
+
+// newHeHua 判断 ganList 里第 i 和第 j 个天干的合化
+func newHeHua(pMonthZhi *TZhi, ganList []heHuaGan, i, j int) *THeHua {
+	a, b := ganList[i], ganList[j]
+	p := &THeHua{
+		ganList:  [2]*TGan{a.pGan, b.pGan},
+		fromList: [2]string{a.strFrom, b.strFrom},
+	}
+	p.pHua, p.strName = a.pGan.HeHua(b.pGan)
+	strPair := fmt.Sprintf("%s%v与%s%v", a.strFrom, a.pGan, b.strFrom, b.pGan)
+
+	// 1. 要挨着
+	if !isHeHuaNear(a, b) {
+		p.nResult = HeHuaBuCheng
+		p.addReason("%s隔位, 遥合不成", strPair)
+		return p
+	}
+
+	// 2. 争合 妒合, 有第三个字也挨着来合其中一个
+	for _, n := range [2]int{i, j} {
+		for k, other := range ganList {
+			if k == i || k == j || !isHeHuaNear(other, ganList[n]) || !other.pGan.CombinesWith(ganList[n].pGan) {
+				continue
+			}
+			p.nResult = HeHuaBuCheng
+			strName := "妒合"
+			if other.pGan.Value()%2 == 0 {
+				strName = "争合"
+			}
+			p.addReason("%s%v又合%s%v, 两%v%s一%v, 合不成", other.strFrom, other.pGan, ganList[n].strFrom, ganList[n].pGan, other.pGan, strName, ganList[n].pGan)
+			return p
+		}
+	}
+	p.addReason("%s, %s", strPair, p.strName)
+
+	// 3. 化神要当令
+	p.nResult = HeHuaHua
+	if pMonthZhi.ToWuXing().Value() != p.pHua.Value() {
+		p.nResult = HeHuaBuHua
+		p.addReason("月令%v属%v, 化神%v不得令, 合而不化", pMonthZhi, pMonthZhi.ToWuXing(), p.pHua)
+		return p
+	}
+	p.addReason("月令%v属%v, 化神得令", pMonthZhi, p.pHua)
+
+	// 4. 其余天干不能克化神
+	for k, other := range ganList {
+		if k != i && k != j && other.pGan.ToWuXing().Controls(p.pHua) {
+			p.nResult = HeHuaBuHua
+			p.addReason("%s%v克化神%v, 合而不化", other.strFrom, other.pGan, p.pHua)
+			return p
+		}
+	}
+	p.addReason("天干不见克%v之字", p.pHua)
+	return p
+}
+
+// addReason 添加一条依据
+func (m *THeHua) addReason(strFormat string, args ...interface{}) {
+	m.reasonList = append(m.reasonList, fmt.Sprintf(strFormat, args...))
+}
+
+// GanList 相合的两个天干
+func (m *THeHua) GanList() [2]*TGan {
+	return m.ganList
+}
+
+// FromList 两个天干的位置 年干 月干 日干 时干 大运 流年
+func (m *THeHua) FromList() [2]string {
+	return m.fromList
+}
+
+// Has 某个位置的天干有没有参与这一对
+func (m *THeHua) Has(strFrom string) bool {
+	return m.fromList[0] == strFrom || m.fromList[1] == strFrom
+}
+
+// Hua 化神
+func (m *THeHua) Hua() *TWuXing {
+	return m.pHua
+}
+
+// Name 比如 甲己合化土
+func (m *THeHua) Name() string {
+	return m.strName
+}
+
+// Result HeHuaBuCheng HeHuaBuHua HeHuaHua
+func (m *THeHua) Result() int {
+	return m.nResult
+}
+
+// ResultName 合不成 合而不化 合化
+func (m *THeHua) ResultName() string {
+	return GetHeHuaResultFromNumber(m.nResult)
+}
+
+// IsHua 合化成功
+func (m *THeHua) IsHua() bool {
+	return m.nResult == HeHuaHua
+}
+
+// IsBound 合住了, 合化或者合而不化, 两干都被绊住
+func (m *THeHua) IsBound() bool {
+	return m.nResult != HeHuaBuCheng
+}
+
+// WuXingList 合化以后两干实际的五行, 合化的变成化神, 其他情况不变
+func (m *THeHua) WuXingList() [2]*TWuXing {
+	if m.IsHua() {
+		return [2]*TWuXing{m.pHua, m.pHua}
+	}
+	return [2]*TWuXing{m.ganList[0].ToWuXing(), m.ganList[1].ToWuXing()}
+}
+
+// Reasons 判断的依据
+func (m *THeHua) Reasons() []string {
+	return m.reasonList
+}
+
+// String 打印用
+func (m *THeHua) String() string {
+	return fmt.Sprintf("%s%v%s%v %s %s", m.fromList[0], m.ganList[0], m.fromList[1], m.ganList[1], m.strName, m.ResultName())
+}
+
+// MarshalJSON JSON 序列化
+func (m *THeHua) MarshalJSON() ([]byte, error) {
+	wuxingList := m.WuXingList()
+	return json.Marshal(struct {
+		Gan     [2]string `json:"gan"`
+		From    [2]string `json:"from"`
+		Name    string    `json:"name"`
+		Hua     string    `json:"hua"`
+		Result  string    `json:"result"`
+		Bound   bool      `json:"bound"`
+		WuXing  [2]string `json:"wuXing"` // 合化以后的五行
+		Reasons []string  `json:"reasons"`
+	}{
+		[2]string{m.ganList[0].String(), m.ganList[1].String()},
+		m.fromList,
+		m.strName,
+		m.pHua.String(),
+		m.ResultName(),
+		m.IsBound(),
+		[2]string{wuxingList[0].String(), wuxingList[1].String()},
+		m.reasonList,
+	})
+}
+
+// HeHuaList 命局天干的合化
+func (m *TSiZhu) HeHuaList() []*THeHua {
+	return CalcHeHua(m, nil, nil)
+}
+
+// HeHuaList 某一年命局 大运 流年天干的合化, 大运取这一年所在的那步, 未起运不看大运
+func (m *TBazi) HeHuaList(nYear int) []*THeHua {
+	var pDaYun *TGanZhi
+	if nIndex := m.DaYunIndexOfYear(nYear); nIndex >= 0 {
+		pDaYun = m.pDaYun.Zhu(nIndex).GanZhi()
+	}
+	return CalcHeHua(m.pSiZhu, pDaYun, NewGanZhiFromYear(nYear))
+}
@@ -0,0 +1,89 @@
+package bazi
+
+import (
+	"strings"
+	"testing"
+)
+
+// heHuaText 合化结果的简写, 一对一行
+func heHuaText(heHuaList []*THeHua) string {
+	var strList []string
+	for _, pHeHua := range heHuaList {
+		strList = append(strList, pHeHua.String())
+	}
+	return strings.Join(strList, "\n")
+}
+
+// TestHeHuaSiZhu 命局天干的合化
+func TestHeHuaSiZhu(t *testing.T) {
+	cases := []struct {
+		nYear, nMonth, nDay int
+		strWant             string
+		strReason           string // 最后一条依据
+		strWuXing           string // 合化以后两干的五行
+	}{
+		// 己丑 丙子 丙申 甲午, 年干时干隔着两柱
+		{1950, 1, 1, "年干己时干甲 甲己合化土 合不成", "年干己与时干甲隔位, 遥合不成", "土木"},
+		// 庚寅 乙酉 戊申 戊午, 酉月金当令, 戊土生金不克
+		{1950, 9, 10, "年干庚月干乙 庚乙合化金 合化", "天干不见克金之字", "金金"},
+		// 庚寅 乙酉 丁巳 丙午, 金当令但日干丁火克金
+		{1950, 9, 19, "年干庚月干乙 庚乙合化金 合而不化", "日干丁克化神金, 合而不化", "金木"},
+		// 庚寅 丙戌 辛巳 甲午, 戌月土当令, 水不得令
+		{1950, 10, 13, "月干丙日干辛 丙辛合化水 合而不化", "月令戌属土, 化神水不得令, 合而不化", "火金"},
+	}
+	for _, c := range cases {
+		heHuaList := GetBazi(c.nYear, c.nMonth, c.nDay, 12, 0, 0, 1).SiZhu().HeHuaList()
+		if got := heHuaText(heHuaList); got != c.strWant {
+			t.Errorf("%d-%d-%d HeHuaList = %q, want %q", c.nYear, c.nMonth, c.nDay, got, c.strWant)
+			continue
+		}
+		pHeHua := heHuaList[0]
+		if reasonList := pHeHua.Reasons(); reasonList[len(reasonList)-1] != c.strReason {
+			t.Errorf("%d-%d-%d Reasons = %v, want last %q", c.nYear, c.nMonth, c.nDay, reasonList, c.strReason)
+		}
+		wuxingList := pHeHua.WuXingList()
+		if got := wuxingList[0].String() + wuxingList[1].String(); got != c.strWuXing {
+			t.Errorf("%d-%d-%d WuXingList = %s, want %s", c.nYear, c.nMonth, c.nDay, got, c.strWuXing)
+		}
+		if pHeHua.IsBound() != (pHeHua.Result() != HeHuaBuCheng) || pHeHua.IsHua() != (pHeHua.Result() == HeHuaHua) {
+			t.Errorf("%d-%d-%d IsBound = %v IsHua = %v, result %s", c.nYear, c.nMonth, c.nDay, pHeHua.IsBound(), pHeHua.IsHua(), pHeHua.ResultName())
+		}
+	}
+}
+
+// TestHeHuaXingYun 庚午 辛巳 壬午 甲辰 这个命局遇到的大运流年天干
+func TestHeHuaXingYun(t *testing.T) {
+	pSiZhu := GetBazi(1990, 5, 17, 8, 0, 0, 1).SiZhu()
+	if got := CalcHeHua(pSiZhu, nil, nil); len(got) != 0 {
+		t.Fatalf("命局 HeHua = %v, want empty", got)
+	}
+	cases := []struct {
+		nDaYun, nLiuNian int
+		strWant          string
+		strReason        string // 第一对的最后一条依据
+	}{
+		// 行运和命局哪一柱都算挨着, 巳月火当令, 化神水不得令
+		{-1, 12, "月干辛流年丙 丙辛合化水 合而不化", "月令巳属火, 化神水不得令, 合而不化"},
+		// 大运丙子 流年丙寅, 两丙争合一辛
+		{12, 2, "月干辛大运丙 丙辛合化水 合不成\n月干辛流年丙 丙辛合化水 合不成", "流年丙又合月干辛, 两丙争合一辛, 合不成"},
+		// 大运乙丑 流年乙亥, 两乙妒合一庚
+		{1, 11, "年干庚大运乙 庚乙合化金 合不成\n年干庚流年乙 庚乙合化金 合不成", "流年乙又合年干庚, 两乙妒合一庚, 合不成"},
+		// 大运戊辰 流年癸酉, 火当令, 但日干壬水克火
+		{4, 9, "大运戊流年癸 戊癸合化火 合而不化", "日干壬克化神火, 合而不化"},
+	}
+	for _, c := range cases {
+		var pDaYun *TGanZhi
+		if c.nDaYun >= 0 {
+			pDaYun = NewGanZhi(c.nDaYun)
+		}
+		pLiuNian := NewGanZhi(c.nLiuNian)
+		heHuaList := CalcHeHua(pSiZhu, pDaYun, pLiuNian)
+		if got := heHuaText(heHuaList); got != c.strWant {
+			t.Errorf("大运%v 流年%v HeHua = %q, want %q", pDaYun, pLiuNian, got, c.strWant)
+			continue
+		}
+		if reasonList := heHuaList[0].Reasons(); reasonList[len(reasonList)-1] != c.strReason {
+			t.Errorf("大运%v 流年%v Reasons = %v, want last %q", pDaYun, pLiuNian, reasonList, c.strReason)
+		}
+	}
+}
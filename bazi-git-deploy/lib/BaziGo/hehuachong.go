@@ -1,5 +1,8 @@
 package bazi
 
+// 干支的合化冲刑害
+// 所有的关系都在这里查表, 其他地方一律用 TGan TZhi 上的方法, 不要再自己写一份
+
 // THeHuaChong 荷花冲
 type THeHuaChong struct {
 }
@@ -8,39 +11,87 @@ type THeHuaChong struct {
 type TTianGanWuHe struct {
 }
 
-// 甲己合化土， 乙庚合化金， 丙辛合化水， 丁壬合化木， 戊癸合化火。
-func quickCheckTianGan(pGan1, pGan2 *TGan) (int, string) {
-	nGan1 := pGan1.Value()
-	nGan2 := pGan2.Value()
+// 天干五合 甲己 乙庚 丙辛 丁壬 戊癸
+var tianganwuhelist = [10]int{5, 6, 7, 8, 9, 0, 1, 2, 3, 4}
 
-	if nGan1 == nGan2 {
-		return -1, "" // 相同不合
-	}
+// 天干五合化出的五行 甲己合化土， 乙庚合化金， 丙辛合化水， 丁壬合化木， 戊癸合化火。 下标是 天干%5
+var tianganhehualist = [5]int{4, 0, 2, 1, 3}
 
-	nGan1 %= 5
-	nGan2 %= 5
+// 天干四冲 甲庚 乙辛 丙壬 丁癸, 戊己居中不冲
+var tianganchonglist = [10]int{6, 7, 8, 9, -1, -1, 0, 1, 2, 3}
 
-	if nGan1 < 0 {
-		nGan1 += 5
-	}
-	if nGan2 < 0 {
-		nGan2 += 5
+// 地支六合 子丑 寅亥 卯戌 辰酉 巳申 午未
+var dizhiliuhelist = [12]int{1, 0, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// 地支六冲 子午 丑未 寅申 卯酉 辰戌 巳亥
+var dizhiliuchonglist = [12]int{6, 7, 8, 9, 10, 11, 0, 1, 2, 3, 4, 5}
+
+// 地支六害 子未 丑午 寅巳 卯辰 申亥 酉戌
+var dizhiliuhailist = [12]int{7, 6, 5, 4, 3, 2, 1, 0, 11, 10, 9, 8}
+
+// 地支三刑, 每个地支刑哪个地支
+// 寅刑巳 巳刑申 申刑寅 无恩之刑, 丑刑戌 戌刑未 未刑丑 恃势之刑, 子卯相刑 无礼之刑, 辰午酉亥 自刑
+var dizhisanxinglist = [12]int{3, 10, 5, 0, 4, 8, 6, 1, 2, 9, 7, 11}
+
+// 地支三合局的五行 申子辰水 巳酉丑金 寅午戌火 亥卯未木, 下标是 地支%4
+var dizhisanhelist = [4]int{2, 0, 3, 1}
+
+// CombinesWith 天干五合
+func (m *TGan) CombinesWith(other *TGan) bool {
+	return tianganwuhelist[m.Value()] == other.Value()
+}
+
+// HeHua 天干五合化出的五行和说明, 比如 甲己合化土, 不合时返回 nil
+func (m *TGan) HeHua(other *TGan) (*TWuXing, string) {
+	if !m.CombinesWith(other) {
+		return nil, ""
 	}
 
-	if nGan1 == nGan2 {
-		switch nGan1 {
-		case 0:
-			return 4, "甲己合化土" // 甲 阳木 + 己 阴土 = 合化土
-		case 1:
-			return 0, "庚乙合化金" // 庚 阳金 + 乙 阴木 = 合化金
-		case 2:
-			return 2, "丙辛合化水" // 丙 阳火 + 辛 阴金 = 合化水
-		case 3:
-			return 1, "壬丁合化木" // 壬 阳水 + 丁 阴火 = 合化木
-		case 4:
-			return 3, "戊癸合化火" // 戊 阳土 + 癸 阴水 = 合化火
-		}
+	// 说明里阳干在前
+	pYang, pYin := m, other
+	if pYang.Value()%2 == 1 {
+		pYang, pYin = pYin, pYang
 	}
+	pWuXing := NewWuXing(tianganhehualist[m.Value()%5])
+	return pWuXing, pYang.String() + pYin.String() + "合化" + pWuXing.String()
+}
+
+// Clashes 天干相冲
+func (m *TGan) Clashes(other *TGan) bool {
+	return tianganchonglist[m.Value()] == other.Value()
+}
+
+// CombinesWith 地支六合
+func (m *TZhi) CombinesWith(other *TZhi) bool {
+	return dizhiliuhelist[m.Value()] == other.Value()
+}
+
+// TrinesWith 地支三合(两支即半合), 同一个三合局的两个不同地支
+func (m *TZhi) TrinesWith(other *TZhi) bool {
+	return m.Value() != other.Value() && m.Value()%4 == other.Value()%4
+}
+
+// TrineWuXing 地支所在三合局的五行
+func (m *TZhi) TrineWuXing() *TWuXing {
+	return NewWuXing(dizhisanhelist[m.Value()%4])
+}
+
+// Clashes 地支六冲
+func (m *TZhi) Clashes(other *TZhi) bool {
+	return dizhiliuchonglist[m.Value()] == other.Value()
+}
+
+// Harms 地支六害
+func (m *TZhi) Harms(other *TZhi) bool {
+	return dizhiliuhailist[m.Value()] == other.Value()
+}
+
+// Punishes 地支相刑, 有方向, 寅刑巳但巳不刑寅. 自刑是和自己相刑
+func (m *TZhi) Punishes(other *TZhi) bool {
+	return dizhisanxinglist[m.Value()] == other.Value()
+}
 
-	return -1, ""
+// PunishesEither 两个地支之间有刑, 不论方向
+func (m *TZhi) PunishesEither(other *TZhi) bool {
+	return m.Punishes(other) || other.Punishes(m)
 }
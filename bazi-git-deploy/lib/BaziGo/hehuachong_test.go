@@ -0,0 +1,124 @@
+package bazi
+
+import (
+	"strings"
+	"testing"
+)
+
+// pairSet 把 "甲己 乙庚" 这样的写法变成两两成对的集合, 两个方向都算
+func pairSet(strPairs string) map[string]bool {
+	result := map[string]bool{}
+	for _, strPair := range strings.Fields(strPairs) {
+		r := []rune(strPair)
+		result[string(r[0])+string(r[1])] = true
+		result[string(r[1])+string(r[0])] = true
+	}
+	return result
+}
+
+// orderedPairSet 把 "寅巳 巳申" 这样的写法变成有方向的集合, 寅巳 算但 巳寅 不算
+func orderedPairSet(strPairs string) map[string]bool {
+	result := map[string]bool{}
+	for _, strPair := range strings.Fields(strPairs) {
+		result[strPair] = true
+	}
+	return result
+}
+
+// TestGanRelations 天干五合 四冲, 十个天干两两都查一遍
+func TestGanRelations(t *testing.T) {
+	heList := pairSet("甲己 乙庚 丙辛 丁壬 戊癸")
+	chongList := pairSet("甲庚 乙辛 丙壬 丁癸")
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			pGan1, pGan2 := NewGan(i), NewGan(j)
+			strPair := pGan1.String() + pGan2.String()
+			if got := pGan1.CombinesWith(pGan2); got != heList[strPair] {
+				t.Errorf("%s CombinesWith = %v", strPair, got)
+			}
+			if got := pGan1.Clashes(pGan2); got != chongList[strPair] {
+				t.Errorf("%s Clashes = %v", strPair, got)
+			}
+		}
+	}
+}
+
+// TestGanHeHua 合化出的五行和说明
+func TestGanHeHua(t *testing.T) {
+	cases := map[string]string{
+		"甲己": "甲己合化土", "己甲": "甲己合化土",
+		"乙庚": "庚乙合化金", "丙辛": "丙辛合化水",
+		"丁壬": "壬丁合化木", "癸戊": "戊癸合化火",
+	}
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			pGan1, pGan2 := NewGan(i), NewGan(j)
+			strPair := pGan1.String() + pGan2.String()
+			strWant, ok := cases[strPair]
+			if !ok {
+				continue
+			}
+			pWuXing, strName := pGan1.HeHua(pGan2)
+			if strName != strWant || pWuXing.String() != string([]rune(strWant)[4]) {
+				t.Errorf("%s HeHua = %v %s, want %s", strPair, pWuXing, strName, strWant)
+			}
+		}
+	}
+	if pWuXing, strName := NewGan(0).HeHua(NewGan(1)); pWuXing != nil || strName != "" {
+		t.Errorf("甲乙 HeHua = %v %s, want nothing", pWuXing, strName)
+	}
+}
+
+// TestZhiRelations 地支六合 三合 六冲 六害, 十二地支两两都查一遍
+func TestZhiRelations(t *testing.T) {
+	heList := pairSet("子丑 寅亥 卯戌 辰酉 巳申 午未")
+	sanHeList := pairSet("申子 子辰 申辰 巳酉 酉丑 巳丑 寅午 午戌 寅戌 亥卯 卯未 亥未")
+	chongList := pairSet("子午 丑未 寅申 卯酉 辰戌 巳亥")
+	haiList := pairSet("子未 丑午 寅巳 卯辰 申亥 酉戌")
+	for i := 0; i < 12; i++ {
+		for j := 0; j < 12; j++ {
+			pZhi1, pZhi2 := NewZhi(i), NewZhi(j)
+			strPair := pZhi1.String() + pZhi2.String()
+			if got := pZhi1.CombinesWith(pZhi2); got != heList[strPair] {
+				t.Errorf("%s CombinesWith = %v", strPair, got)
+			}
+			if got := pZhi1.TrinesWith(pZhi2); got != sanHeList[strPair] {
+				t.Errorf("%s TrinesWith = %v", strPair, got)
+			}
+			if got := pZhi1.Clashes(pZhi2); got != chongList[strPair] {
+				t.Errorf("%s Clashes = %v", strPair, got)
+			}
+			if got := pZhi1.Harms(pZhi2); got != haiList[strPair] {
+				t.Errorf("%s Harms = %v", strPair, got)
+			}
+		}
+	}
+}
+
+// TestZhiTrineWuXing 三合局的五行
+func TestZhiTrineWuXing(t *testing.T) {
+	want := "水金火木水金火木水金火木" // 子丑寅卯辰巳午未申酉戌亥
+	for i := 0; i < 12; i++ {
+		if got := NewZhi(i).TrineWuXing().String(); got != string([]rune(want)[i]) {
+			t.Errorf("%v TrineWuXing = %s, want %s", NewZhi(i), got, string([]rune(want)[i]))
+		}
+	}
+}
+
+// TestZhiPunishes 三刑有方向, 自刑和自己刑
+func TestZhiPunishes(t *testing.T) {
+	xingList := orderedPairSet("寅巳 巳申 申寅 丑戌 戌未 未丑 子卯 卯子 辰辰 午午 酉酉 亥亥")
+	for i := 0; i < 12; i++ {
+		for j := 0; j < 12; j++ {
+			pZhi1, pZhi2 := NewZhi(i), NewZhi(j)
+			strPair := pZhi1.String() + pZhi2.String()
+			strBack := pZhi2.String() + pZhi1.String()
+			if got := pZhi1.Punishes(pZhi2); got != xingList[strPair] {
+				t.Errorf("%s Punishes = %v", strPair, got)
+			}
+			if got := pZhi1.PunishesEither(pZhi2); got != (xingList[strPair] || xingList[strBack]) {
+				t.Errorf("%s PunishesEither = %v", strPair, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package bazi
+
+import "fmt"
+
+// 合婚
+// 合婚是把两个人的八字放在一起看是否相配, 常看的几项:
+// 1. 年支 日支(夫妻宫) 之间的 六合 三合 六冲 六害
+// 2. 日干是否天干五合
+// 3. 五行强弱是否互补, 一方缺的正好是另一方旺的
+// 4. 对方日干是不是自己的配偶星, 男命以财为妻, 女命以官为夫
+// 5. 年柱纳音五行的生克, 受克反成 不怕克的另算
+
+// 合婚的基础分, 各项在此基础上加减
+const heHunBaseScore = 60
+
+// THeHunItem 合婚的一项依据
+type THeHunItem struct {
+	Category string `json:"category"` // 类别 年支 日支 日干 五行 十神 纳音
+	Name     string `json:"name"`     // 关系名称 比如 六合 六冲
+	Score    int    `json:"score"`    // 加减分
+	Reason   string `json:"reason"`   // 说明
+}
+
+// NewHeHun 新建合婚
+func NewHeHun(pBazi1 *TBazi, pBazi2 *TBazi) *THeHun {
+	p := &THeHun{
+		pBazi1: pBazi1,
+		pBazi2: pBazi2,
+	}
+	p.init()
+	return p
+}
+
+// THeHun 合婚
+type THeHun struct {
+	pBazi1   *TBazi
+	pBazi2   *TBazi
+	nScore   int           // 总分 0-100
+	itemList []*THeHunItem // 评分依据
+}
+
+func (m *THeHun) init() *THeHun {
+	pSiZhu1 := m.pBazi1.SiZhu()
+	pSiZhu2 := m.pBazi2.SiZhu()
+
+	// 1. 年支 日支
+	m.checkZhi("年支", pSiZhu1.YearZhu().Zhi(), pSiZhu2.YearZhu().Zhi(), 8, 6, -10, -6)
+	m.checkZhi("日支", pSiZhu1.DayZhu().Zhi(), pSiZhu2.DayZhu().Zhi(), 10, 8, -12, -8)
+
+	// 2. 日干五合
+	if pHeHua, strName := pSiZhu1.DayZhu().Gan().HeHua(pSiZhu2.DayZhu().Gan()); pHeHua != nil {
+		m.addItem("日干", "五合", 8, fmt.Sprintf("日干%s, 夫妻情投意合", strName))
+	}
+
+	// 3. 五行互补
+	m.checkWuXing()
+
+	// 4. 配偶星
+	m.checkShiShen(m.pBazi1, m.pBazi2, "甲方")
+	m.checkShiShen(m.pBazi2, m.pBazi1, "乙方")
+
+	// 5. 年柱纳音
+	m.checkNaYin(pSiZhu1.YearZhu().GanZhi().ToNaYin(), pSiZhu2.YearZhu().GanZhi().ToNaYin())
+
+	// 汇总
+	m.nScore = heHunBaseScore
+	for _, item := range m.itemList {
+		m.nScore += item.Score
+	}
+	if m.nScore < 0 {
+		m.nScore = 0
+	}
+	if m.nScore > 100 {
+		m.nScore = 100
+	}
+	return m
+}
+
+// addItem 添加一项依据
+func (m *THeHun) addItem(strCategory string, strName string, nScore int, strReason string) {
+	m.itemList = append(m.itemList, &THeHunItem{
+		Category: strCategory,
+		Name:     strName,
+		Score:    nScore,
+		Reason:   strReason,
+	})
+}
+
+// checkZhi 两支之间的 六合 三合 六冲 六害
+func (m *THeHun) checkZhi(strCategory string, pZhi1 *TZhi, pZhi2 *TZhi, nLiuHe, nSanHe, nChong, nHai int) {
+	strPair := pZhi1.String() + pZhi2.String()
+
+	if pZhi1.CombinesWith(pZhi2) {
+		m.addItem(strCategory, "六合", nLiuHe, strPair+"六合")
+	}
+	if pZhi1.TrinesWith(pZhi2) {
+		m.addItem(strCategory, "三合", nSanHe, strPair+"三合")
+	}
+	if pZhi1.Clashes(pZhi2) {
+		m.addItem(strCategory, "六冲", nChong, strPair+"相冲")
+	}
+	if pZhi1.Harms(pZhi2) {
+		m.addItem(strCategory, "六害", nHai, strPair+"相害")
+	}
+}
+
+// checkWuXing 一方最旺的五行是否补了另一方最弱的五行
+func (m *THeHun) checkWuXing() {
+	nWeak1, nStrong1 := getWeakStrongWuXing(m.pBazi1.SiZhu().XiYong())
+	nWeak2, nStrong2 := getWeakStrongWuXing(m.pBazi2.SiZhu().XiYong())
+
+	if nStrong2 == nWeak1 {
+		m.addItem("五行", "互补", 6, fmt.Sprintf("甲方五行%s最弱, 乙方%s最旺, 正好补足", GetWuXingFromNumber(nWeak1), GetWuXingFromNumber(nStrong2)))
+	}
+	if nStrong1 == nWeak2 {
+		m.addItem("五行", "互补", 6, fmt.Sprintf("乙方五行%s最弱, 甲方%s最旺, 正好补足", GetWuXingFromNumber(nWeak2), GetWuXingFromNumber(nStrong1)))
+	}
+	if nStrong1 == nStrong2 {
+		m.addItem("五行", "偏枯", -4, fmt.Sprintf("双方都是%s最旺, 旺上加旺", GetWuXingFromNumber(nStrong1)))
+	}
+}
+
+// getWeakStrongWuXing 最弱和最旺的五行
+func getWeakStrongWuXing(pXiYong *TXiYong) (int, int) {
+	wuxingList := pXiYong.WuXingList()
+	nWeak, nStrong := 0, 0
+	for i := 1; i < len(wuxingList); i++ {
+		if wuxingList[i] < wuxingList[nWeak] {
+			nWeak = i
+		}
+		if wuxingList[i] > wuxingList[nStrong] {
+			nStrong = i
+		}
+	}
+	return nWeak, nStrong
+}
+
+// checkShiShen 对方日干对自己日干的十神, 男命以财为妻, 女命以官杀为夫
+func (m *THeHun) checkShiShen(pSelf *TBazi, pOther *TBazi, strWho string) {
+	pShiShen := NewShiShenFromGan(pSelf.SiZhu().DayZhu().Gan().Value(), pOther.SiZhu().DayZhu().Gan())
+	strShiShen := GetShiShenLongFromNumber(pShiShen.Value())
+
+	switch {
+	case pSelf.Sex() == 1 && pShiShen.Value() == 5, pSelf.Sex() != 1 && pShiShen.Value() == 7:
+		m.addItem("十神", strShiShen, 8, fmt.Sprintf("对方日干是%s的%s, 正配偶星", strWho, strShiShen))
+	case pSelf.Sex() == 1 && pShiShen.Value() == 4, pSelf.Sex() != 1 && pShiShen.Value() == 6:
+		m.addItem("十神", strShiShen, 4, fmt.Sprintf("对方日干是%s的%s, 偏配偶星", strWho, strShiShen))
+	case pShiShen.Value() == 0 || pShiShen.Value() == 1:
+		m.addItem("十神", strShiShen, -4, fmt.Sprintf("对方日干是%s的%s, 比劫相争", strWho, strShiShen))
+	}
+}
+
+// checkNaYin 年柱纳音五行生克, 受克反成的算吉, 不怕克的不扣分
+func (m *THeHun) checkNaYin(pNaYin1 *TNaYin, pNaYin2 *TNaYin) {
+	pGuanXi := NewNaYinGuanXi(pNaYin1, "甲方", pNaYin2, "乙方")
+	strPair := pNaYin1.String() + "与" + pNaYin2.String()
+
+	switch {
+	case pGuanXi.IsSheng():
+		m.addItem("纳音", "相生", 6, strPair+"纳音相生")
+	case pGuanXi.Name() == NaYinBiHe:
+		m.addItem("纳音", "比和", 3, strPair+"纳音比和")
+	case pGuanXi.KeType() == NaYinKeXi:
+		m.addItem("纳音", "克中有成", 3, pGuanXi.Desc())
+	case pGuanXi.KeType() == NaYinKeBuPa:
+		m.addItem("纳音", "相克无妨", 0, pGuanXi.Desc())
+	default:
+		m.addItem("纳音", "相克", -6, strPair+"纳音相克")
+	}
+}
+
+// Score 总分 0-100
+func (m *THeHun) Score() int {
+	return m.nScore
+}
+
+// Level 等级
+func (m *THeHun) Level() string {
+	switch {
+	case m.nScore >= 80:
+		return "上等婚"
+	case m.nScore >= 60:
+		return "中等婚"
+	}
+	return "下等婚"
+}
+
+// Items 评分依据
+func (m *THeHun) Items() []*THeHunItem {
+	return m.itemList
+}
+
+// String 打印用
+func (m *THeHun) String() string {
+	strResult := fmt.Sprintf("合婚: %d分 %s\n", m.nScore, m.Level())
+	for _, item := range m.itemList {
+		strResult += fmt.Sprintf("[%s] %s %+d %s\n", item.Category, item.Name, item.Score, item.Reason)
+	}
+	return strResult
+}
@@ -0,0 +1,66 @@
+package bazi
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestHeHun 男命 1990-5-17 8点 庚午 辛巳 壬午 甲辰 和几个女命合婚
+func TestHeHun(t *testing.T) {
+	pMale := GetBazi(1990, 5, 17, 8, 0, 0, 1)
+	cases := []struct {
+		nYear, nMonth, nDay, nHour int
+		nScore                     int
+		strLevel                   string
+		strItems                   string // 类别 名称 加减分
+	}{
+		// 壬申 壬寅 丁丑 丙午: 午丑害, 壬丁合, 都是火最旺, 丁是壬的正财 壬是丁的正官, 路旁土生剑锋金
+		{1992, 3, 2, 12, 78, "中等婚", "日支六害-8 日干五合+8 五行偏枯-4 十神正财+8 十神正官+8 纳音相生+6"},
+		// 壬申 癸卯 壬午 丙午: 两个壬日比肩相争
+		{1992, 3, 7, 12, 54, "下等婚", "五行偏枯-4 十神比肩-4 十神比肩-4 纳音相生+6"},
+		// 辛未 乙未 乙巳 辛巳: 午未合, 年柱都是路旁土
+		{1991, 8, 3, 10, 71, "中等婚", "年支六合+8 纳音比和+3"},
+		// 戊辰 癸亥 甲午 丁卯: 大林木克路旁土, 土得木而成
+		{1988, 12, 5, 6, 63, "中等婚", "纳音克中有成+3"},
+		// 癸卯 甲子 甲子 庚午: 日支子午冲
+		{2024, 1, 1, 12, 54, "下等婚", "日支六冲-12 纳音相生+6"},
+	}
+	for _, c := range cases {
+		pHeHun := NewHeHun(pMale, GetBazi(c.nYear, c.nMonth, c.nDay, c.nHour, 0, 0, 0))
+		var strList []string
+		for _, item := range pHeHun.Items() {
+			strList = append(strList, fmt.Sprintf("%s%s%+d", item.Category, item.Name, item.Score))
+		}
+		if got := strings.Join(strList, " "); got != c.strItems {
+			t.Errorf("%d-%d-%d Items = %s, want %s", c.nYear, c.nMonth, c.nDay, got, c.strItems)
+		}
+		if pHeHun.Score() != c.nScore || pHeHun.Level() != c.strLevel {
+			t.Errorf("%d-%d-%d Score = %d %s, want %d %s", c.nYear, c.nMonth, c.nDay, pHeHun.Score(), pHeHun.Level(), c.nScore, c.strLevel)
+		}
+	}
+}
+
+// TestHeHunShangDeng 甲子 丁丑 己未 庚午 和 戊辰 癸亥 甲午 丁卯, 年支子辰三合 日支未午六合 甲己合, 木补木
+func TestHeHunShangDeng(t *testing.T) {
+	pHeHun := NewHeHun(GetBazi(1985, 1, 20, 12, 0, 0, 1), GetBazi(1988, 12, 5, 6, 0, 0, 0))
+	if pHeHun.Score() != 84 || pHeHun.Level() != "上等婚" {
+		t.Errorf("Score = %d %s, want 84 上等婚", pHeHun.Score(), pHeHun.Level())
+	}
+	wantList := []string{
+		"子辰三合",
+		"未午六合",
+		"日干甲己合化土, 夫妻情投意合",
+		"甲方五行木最弱, 乙方木最旺, 正好补足",
+		"海中金与大林木纳音相克",
+	}
+	itemList := pHeHun.Items()
+	if len(itemList) != len(wantList) {
+		t.Fatalf("Items = %v", pHeHun)
+	}
+	for i, strWant := range wantList {
+		if itemList[i].Reason != strWant {
+			t.Errorf("Items[%d].Reason = %s, want %s", i, itemList[i].Reason, strWant)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package bazi
+
+// 儒略日
+// 儒略日数(JDN)是从公元前4713年1月1日(儒略历)正午开始连续计数的日数, 天文计算通用.
+// 这里用它作为日期换算的核心: 年月日与日数之间的互转都是 O(1) 的公式, 不需要循环和二分.
+// 1582 年 10 月 4 日(儒略历)之后的一天是 10 月 15 日(格里高利历), 前后儒略日数连续.
+// 公元前的年份没有公元0年, 公元前1年在天文纪年里是0年, 公元前2年是-1年, 以此类推.
+
+// jdnOfAllDaysOrigin GetAllDays 的第0日(公元前1年12月31日)对应的儒略日数
+const jdnOfAllDaysOrigin = 1721423
+
+// jdnOfGregorianStart 1582 年 10 月 15 日, 格里高利历第一天的儒略日数
+const jdnOfGregorianStart = 2299161
+
+// mjdOffset 简化儒略日(MJD)与儒略日(JD)的差
+const mjdOffset = 2400000.5
+
+// floorDiv 向下取整的除法, 公元前的负数日期需要
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// floorDiv64 向下取整的除法, 64位版本
+func floorDiv64(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// GetJulianDayNumber 从新历年月日获得儒略日数
+// 1582 年 10 月 15 日之前按儒略历计算, 之后按格里高利历计算
+func GetJulianDayNumber(nYear, nMonth, nDay int) int {
+	// 没有公元0年, 换成天文纪年
+	if nYear < 0 {
+		nYear++
+	}
+
+	// 以3月为一年的开始, 闰日就落在年末
+	a := floorDiv(14-nMonth, 12)
+	y := nYear + 4800 - a
+	m := nMonth + 12*a - 3
+
+	nJDN := nDay + (153*m+2)/5 + 365*y + floorDiv(y, 4)
+	if nJDN-32083 < jdnOfGregorianStart {
+		return nJDN - 32083 // 儒略历
+	}
+	return nJDN - floorDiv(y, 100) + floorDiv(y, 400) - 32045 // 格里高利历
+}
+
+// GetDateFromJulianDayNumber 从儒略日数反推新历年月日
+func GetDateFromJulianDayNumber(nJDN int) (int, int, int) {
+	var b, c int
+	if nJDN >= jdnOfGregorianStart {
+		// 格里高利历, 先扣掉世纪年的闰日
+		a := nJDN + 32044
+		b = floorDiv(4*a+3, 146097)
+		c = a - floorDiv(146097*b, 4)
+	} else {
+		// 儒略历
+		b = 0
+		c = nJDN + 32082
+	}
+
+	d := floorDiv(4*c+3, 1461)
+	e := c - floorDiv(1461*d, 4)
+	m := floorDiv(5*e+2, 153)
+
+	nDay := e - floorDiv(153*m+2, 5) + 1
+	nMonth := m + 3 - 12*floorDiv(m, 10)
+	nYear := 100*b + d - 4800 + floorDiv(m, 10)
+
+	// 天文纪年换回公元纪年, 没有公元0年
+	if nYear <= 0 {
+		nYear--
+	}
+	return nYear, nMonth, nDay
+}
+
+// NewSolarDateFromJulianDay 从儒略日(带小数的天文儒略日)创建新历时间, 精确到秒
+func NewSolarDateFromJulianDay(fJD float64) *TSolarDate {
+	// 儒略日从正午开始, 挪半天换成从午夜开始的日数
+	nSeconds := int64((fJD+0.5)*24*60*60 + 0.5)
+	nSeconds -= int64(jdnOfAllDaysOrigin) * 24 * 60 * 60
+	return NewSolarDateFrom64TimeStamp(nSeconds)
+}
+
+// NewSolarDateFromModifiedJulianDay 从简化儒略日创建新历时间, 精确到秒
+func NewSolarDateFromModifiedJulianDay(fMJD float64) *TSolarDate {
+	return NewSolarDateFromJulianDay(fMJD + mjdOffset)
+}
+
+// JulianDayNumber 儒略日数, 当天正午的儒略日
+func (m *TSolarDate) JulianDayNumber() int {
+	return GetJulianDayNumber(m.nYear, m.nMonth, m.nDay)
+}
+
+// JulianDay 儒略日, 带时分秒的小数部分
+func (m *TSolarDate) JulianDay() float64 {
+	nSeconds := m.nHour*60*60 + m.nMinute*60 + m.nSecond
+	return float64(m.JulianDayNumber()) - 0.5 + float64(nSeconds)/(24*60*60)
+}
+
+// ModifiedJulianDay 简化儒略日, 从 1858 年 11 月 17 日零时开始
+func (m *TSolarDate) ModifiedJulianDay() float64 {
+	return m.JulianDay() - mjdOffset
+}
+
+// GetDiffDays 获取两个日期之间相差的天数, 只看日期不看时间
+func (m *TSolarDate) GetDiffDays(other *TSolarDate) int {
+	return other.JulianDayNumber() - m.JulianDayNumber()
+}
@@ -0,0 +1,65 @@
+package bazi
+
+import "testing"
+
+// TestJulianDayNumber 几个公认的儒略日数
+func TestJulianDayNumber(t *testing.T) {
+	cases := []struct {
+		nYear, nMonth, nDay int
+		nJDN                int
+	}{
+		{-4713, 1, 1, 0},       // 儒略日起点
+		{-1, 12, 31, 1721423},  // 公元前1年最后一天, 没有0年
+		{1, 1, 1, 1721424},     // 公元1年1月1日
+		{1582, 10, 4, 2299160}, // 儒略历最后一天
+		{1582, 10, 15, 2299161},
+		{1858, 11, 17, 2400001}, // 简化儒略日起点
+		{2000, 1, 1, 2451545},
+	}
+	for _, c := range cases {
+		if nJDN := GetJulianDayNumber(c.nYear, c.nMonth, c.nDay); nJDN != c.nJDN {
+			t.Errorf("GetJulianDayNumber(%d, %d, %d) = %d, want %d", c.nYear, c.nMonth, c.nDay, nJDN, c.nJDN)
+		}
+		nYear, nMonth, nDay := GetDateFromJulianDayNumber(c.nJDN)
+		if nYear != c.nYear || nMonth != c.nMonth || nDay != c.nDay {
+			t.Errorf("GetDateFromJulianDayNumber(%d) = %d-%d-%d, want %d-%d-%d", c.nJDN, nYear, nMonth, nDay, c.nYear, c.nMonth, c.nDay)
+		}
+	}
+}
+
+// TestJulianDayAgainstLegacy 公元前500年到公元3000年的每一天, 儒略日算法和原来逐年累加的算法一致, 时间戳能来回换算
+func TestJulianDayAgainstLegacy(t *testing.T) {
+	pDate := &TSolarDate{}
+	nLast := NewSolarDate(-501, 12, 31, 0, 0, 0).GetAllDays()
+	for nYear := -500; nYear <= 3000; nYear++ {
+		if nYear == 0 {
+			continue // 公元前1年的下一年就是公元1年, 没有0年
+		}
+		for nMonth := 1; nMonth <= 12; nMonth++ {
+			for nDay := 1; nDay <= pDate.GetMonthDays(nYear, nMonth); nDay++ {
+				if !pDate.GetDateIsValid(nYear, nMonth, nDay) {
+					continue // 1582 年 10 月消失的十天
+				}
+				p := NewSolarDate(nYear, nMonth, nDay, 12, 34, 56)
+
+				nAllDays := p.GetAllDays()
+				if nLegacy := p.GetBasicDays(nYear, nMonth, nDay) + p.GetLeapDays(nYear, nMonth, nDay); nAllDays != nLegacy {
+					t.Fatalf("%d-%d-%d GetAllDays = %d, legacy = %d", nYear, nMonth, nDay, nAllDays, nLegacy)
+				}
+				if nAllDays != nLast+1 {
+					t.Fatalf("%d-%d-%d GetAllDays = %d, not continuous after %d", nYear, nMonth, nDay, nAllDays, nLast)
+				}
+				nLast = nAllDays
+
+				if nJDN := p.JulianDayNumber(); nJDN != nAllDays+jdnOfAllDaysOrigin {
+					t.Fatalf("%d-%d-%d JulianDayNumber = %d, want %d", nYear, nMonth, nDay, nJDN, nAllDays+jdnOfAllDaysOrigin)
+				}
+
+				pBack := NewSolarDateFrom64TimeStamp(p.Get64TimeStamp())
+				if *pBack != *p {
+					t.Fatalf("%d-%d-%d timestamp round trip = %v", nYear, nMonth, nDay, pBack)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package bazi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 六亲
+// 六亲按十神定, 男女命不同: 男命正财为妻 官杀为子女, 女命正官为夫 食伤为子女, 偏财为父 正印为母, 比劫为兄弟姐妹.
+// 四柱又各是一个宫位: 年柱祖上, 月柱父母, 日支夫妻, 时柱子女. 日干是日主自己, 不算六亲.
+// 行运(大运 流年)冲合某个字, 就是冲合这个字代表的六亲, 冲合地支的同时也冲合这个宫位.
+
+// 六亲类别
+const (
+	LiuQinFu      = iota // 父亲
+	LiuQinMu             // 母亲
+	LiuQinPeiOu          // 配偶
+	LiuQinZiNv           // 子女
+	LiuQinXiongDi        // 兄弟姐妹
+	LiuQinQiTa           // 其他
+)
+
+// GetLiuQinCategoryFromNumber 从数字获得六亲类别名
+func GetLiuQinCategoryFromNumber(nValue int) string {
+	switch nValue {
+	case LiuQinFu:
+		return "父亲"
+	case LiuQinMu:
+		return "母亲"
+	case LiuQinPeiOu:
+		return "配偶"
+	case LiuQinZiNv:
+		return "子女"
+	case LiuQinXiongDi:
+		return "兄弟姐妹"
+	case LiuQinQiTa:
+		return "其他"
+	}
+	return ""
+}
+
+// 十神对应的六亲, 第一行女命, 第二行男命
+var liuqinnamelist = [2][10]string{
+	//比肩    劫财    食神    伤官    偏财    正财    七杀    正官    偏印    正印
+	{"姐妹", "兄弟", "女儿", "儿子", "父亲", "婆婆", "偏夫", "丈夫", "继母", "母亲"},
+	{"兄弟", "姐妹", "孙女", "孙子", "父亲", "妻子", "儿子", "女儿", "继母", "母亲"}}
+
+// 十神对应的六亲类别, 和上表对应
+var liuqincategorylist = [2][10]int{
+	{LiuQinXiongDi, LiuQinXiongDi, LiuQinZiNv, LiuQinZiNv, LiuQinFu, LiuQinQiTa, LiuQinPeiOu, LiuQinPeiOu, LiuQinQiTa, LiuQinMu},
+	{LiuQinXiongDi, LiuQinXiongDi, LiuQinQiTa, LiuQinQiTa, LiuQinFu, LiuQinPeiOu, LiuQinZiNv, LiuQinZiNv, LiuQinQiTa, LiuQinMu}}
+
+// 四柱的名字
+var zhunamelist = [4]string{"年柱", "月柱", "日柱", "时柱"}
+
+// 四柱的天干 地支, 说明里用
+var zhuganlist = [4]string{"年干", "月干", "日干", "时干"}
+var zhuzhilist = [4]string{"年支", "月支", "日支", "时支"}
+
+// 四柱的宫位
+var gongweinamelist = [4]string{"祖上宫", "父母宫", "夫妻宫", "子女宫"}
+
+// NewLiuQin 新建六亲, 要在大运排好以后
+func NewLiuQin(pBazi *TBazi) *TLiuQin {
+	p := &TLiuQin{pBazi: pBazi}
+	p.init()
+	return p
+}
+
+// TLiuQin 六亲
+type TLiuQin struct {
+	pBazi    *TBazi
+	itemList []*TLiuQinItem
+}
+
+// TLiuQinItem 命局里代表六亲的一个字
+type TLiuQinItem struct {
+	nZhu      int   // 0-3 年月日时
+	nCangGan  int   // -1 是天干, 0-2 是地支藏干的下标
+	pGan      *TGan // 天干或者藏干
+	pShiShen  *TShiShen
+	strName   string // 六亲 比如 父亲
+	nCategory int    // 六亲类别
+}
+
+// TLiuQinEvent 行运冲合命局的某个位置, 影响这个位置上的六亲
+type TLiuQinEvent struct {
+	strSource   string   // 大运 流年
+	pGanZhi     *TGanZhi // 行运干支
+	nYear       int      // 流年的年份, 大运是起运的年份
+	strRelation string   // 冲 合
+	nZhu        int      // 被冲合的柱
+	isGan       bool     // 冲合的是天干还是地支
+	strTarget   string   // 被冲合的字, 比如 年支子
+	itemList    []*TLiuQinItem
+}
+
+func (m *TLiuQin) init() {
+	nSex := 0
+	if m.pBazi.Sex() == 1 {
+		nSex = 1
+	}
+
+	pSiZhu := m.pBazi.SiZhu()
+	nDayGan := pSiZhu.DayZhu().Gan().Value()
+	for nZhu, pZhu := range m.zhuList() {
+		// 日干是日主自己
+		if nZhu != 2 {
+			m.addItem(nSex, nZhu, -1, NewShiShenFromGan(nDayGan, pZhu.Gan()), pZhu.Gan())
+		}
+		pCangGan := pZhu.CangGan()
+		for i := 0; i < pCangGan.Size(); i++ {
+			m.addItem(nSex, nZhu, i, pCangGan.ShiShen(i), pCangGan.Gan(i))
+		}
+	}
+}
+
+// zhuList 年月日时四柱
+func (m *TLiuQin) zhuList() []*TZhu {
+	pSiZhu := m.pBazi.SiZhu()
+	return []*TZhu{pSiZhu.YearZhu(), pSiZhu.MonthZhu(), pSiZhu.DayZhu(), pSiZhu.HourZhu()}
+}
+
+func (m *TLiuQin) addItem(nSex, nZhu, nCangGan int, pShiShen *TShiShen, pGan *TGan) {
+	m.itemList = append(m.itemList, &TLiuQinItem{
+		nZhu:      nZhu,
+		nCangGan:  nCangGan,
+		pGan:      pGan,
+		pShiShen:  pShiShen,
+		strName:   liuqinnamelist[nSex][pShiShen.Value()],
+		nCategory: liuqincategorylist[nSex][pShiShen.Value()],
+	})
+}
+
+// ItemList 命局里所有代表六亲的字
+func (m *TLiuQin) ItemList() []*TLiuQinItem {
+	return m.itemList
+}
+
+// Find 某一类六亲在命局里的字, 比如 LiuQinFu
+func (m *TLiuQin) Find(nCategory int) []*TLiuQinItem {
+	var result []*TLiuQinItem
+	for _, p := range m.itemList {
+		if p.nCategory == nCategory {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Events 某个行运干支冲合命局的位置和受影响的六亲
+// 天干只看冲合年月时三干, 地支看四支, 地支上的藏干都受影响
+func (m *TLiuQin) Events(strSource string, pGanZhi *TGanZhi, nYear int) []*TLiuQinEvent {
+	pGan, pZhi := pGanZhi.ExtractGanZhi()
+
+	var result []*TLiuQinEvent
+	add := func(strRelation string, nZhu int, isGan bool, strTarget string) {
+		pEvent := &TLiuQinEvent{
+			strSource:   strSource,
+			pGanZhi:     pGanZhi,
+			nYear:       nYear,
+			strRelation: strRelation,
+			nZhu:        nZhu,
+			isGan:       isGan,
+			strTarget:   strTarget,
+		}
+		for _, p := range m.itemList {
+			if p.nZhu == nZhu && (p.nCangGan < 0) == isGan {
+				pEvent.itemList = append(pEvent.itemList, p)
+			}
+		}
+		result = append(result, pEvent)
+	}
+
+	for nZhu, pZhu := range m.zhuList() {
+		if nZhu != 2 {
+			strTarget := zhuganlist[nZhu] + pZhu.Gan().String()
+			switch {
+			case pGan.Clashes(pZhu.Gan()):
+				add("冲", nZhu, true, strTarget)
+			case pGan.CombinesWith(pZhu.Gan()):
+				add("合", nZhu, true, strTarget)
+			}
+		}
+		strTarget := zhuzhilist[nZhu] + pZhu.Zhi().String()
+		switch {
+		case pZhi.Clashes(pZhu.Zhi()):
+			add("冲", nZhu, false, strTarget)
+		case pZhi.CombinesWith(pZhu.Zhi()):
+			add("合", nZhu, false, strTarget)
+		}
+	}
+	return result
+}
+
+// DaYunEvents 从 nFromYear 所在的那步大运开始, 每步大运冲合的六亲
+func (m *TLiuQin) DaYunEvents(nFromYear int) []*TLiuQinEvent {
+	result := []*TLiuQinEvent{}
+	pDaYun := m.pBazi.DaYun()
+	for i := 0; i < pDaYun.Size(); i++ {
+		nYear := m.pBazi.Date().Year() + pDaYun.Age(i)
+		if nYear+10 <= nFromYear {
+			continue
+		}
+		result = append(result, m.Events("大运", pDaYun.Zhu(i).GanZhi(), nYear)...)
+	}
+	return result
+}
+
+// LiuNianEvents 从 nStartYear 开始 nYears 年, 每年流年冲合的六亲
+func (m *TLiuQin) LiuNianEvents(nStartYear, nYears int) []*TLiuQinEvent {
+	result := []*TLiuQinEvent{}
+	for nYear := nStartYear; nYear < nStartYear+nYears; nYear++ {
+		result = append(result, m.Events("流年", NewGanZhiFromYear(nYear), nYear)...)
+	}
+	return result
+}
+
+// String 打印用
+func (m *TLiuQin) String() string {
+	strResult := "六亲:\n"
+	for _, p := range m.itemList {
+		strResult += p.String() + "\n"
+	}
+	return strResult
+}
+
+// MarshalJSON JSON 序列化
+func (m *TLiuQin) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.itemList)
+}
+
+// Zhu 所在的柱 0-3 年月日时
+func (m *TLiuQinItem) Zhu() int {
+	return m.nZhu
+}
+
+// IsGan 是不是天干, 不是就是地支藏干
+func (m *TLiuQinItem) IsGan() bool {
+	return m.nCangGan < 0
+}
+
+// Gan 天干或者藏干
+func (m *TLiuQinItem) Gan() *TGan {
+	return m.pGan
+}
+
+// ShiShen 十神
+func (m *TLiuQinItem) ShiShen() *TShiShen {
+	return m.pShiShen
+}
+
+// Name 六亲 比如 父亲
+func (m *TLiuQinItem) Name() string {
+	return m.strName
+}
+
+// Category 六亲类别
+func (m *TLiuQinItem) Category() int {
+	return m.nCategory
+}
+
+// Palace 所在的宫位
+func (m *TLiuQinItem) Palace() string {
+	return gongweinamelist[m.nZhu]
+}
+
+// position 天干 或者 藏干
+func (m *TLiuQinItem) position() string {
+	if m.IsGan() {
+		return "天干"
+	}
+	return "藏干"
+}
+
+// String 打印用
+func (m *TLiuQinItem) String() string {
+	return fmt.Sprintf("%s%s%v(%s) %s 在%s", zhunamelist[m.nZhu], m.position(), m.pGan, GetShiShenLongFromNumber(m.pShiShen.Value()), m.strName, m.Palace())
+}
+
+// MarshalJSON JSON 序列化
+func (m *TLiuQinItem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Zhu      string `json:"zhu"`
+		Position string `json:"position"`
+		Gan      string `json:"gan"`
+		ShiShen  string `json:"shiShen"`
+		Name     string `json:"name"`
+		Category string `json:"category"`
+		Palace   string `json:"palace"`
+	}{zhunamelist[m.nZhu], m.position(), m.pGan.String(), GetShiShenLongFromNumber(m.pShiShen.Value()), m.strName, GetLiuQinCategoryFromNumber(m.nCategory), m.Palace()})
+}
+
+// Source 大运 或者 流年
+func (m *TLiuQinEvent) Source() string {
+	return m.strSource
+}
+
+// GanZhi 行运干支
+func (m *TLiuQinEvent) GanZhi() *TGanZhi {
+	return m.pGanZhi
+}
+
+// Year 流年的年份, 大运是起运的年份
+func (m *TLiuQinEvent) Year() int {
+	return m.nYear
+}
+
+// Relation 冲 或者 合
+func (m *TLiuQinEvent) Relation() string {
+	return m.strRelation
+}
+
+// ItemList 受影响的六亲
+func (m *TLiuQinEvent) ItemList() []*TLiuQinItem {
+	return m.itemList
+}
+
+// Palace 冲合地支时受影响的宫位, 冲合天干时为空
+func (m *TLiuQinEvent) Palace() string {
+	if m.isGan {
+		return ""
+	}
+	return gongweinamelist[m.nZhu]
+}
+
+// Target 被冲合的字, 比如 年支子
+func (m *TLiuQinEvent) Target() string {
+	return m.strTarget
+}
+
+// String 打印用
+func (m *TLiuQinEvent) String() string {
+	strResult := fmt.Sprintf("%d年%s%v%s%s", m.nYear, m.strSource, m.pGanZhi, m.strRelation, m.strTarget)
+	if strPalace := m.Palace(); strPalace != "" {
+		strResult += "(" + strPalace + ")"
+	}
+	for _, p := range m.itemList {
+		strResult += " " + p.strName
+	}
+	return strResult
+}
+
+// MarshalJSON JSON 序列化
+func (m *TLiuQinEvent) MarshalJSON() ([]byte, error) {
+	nameList := []string{}
+	for _, p := range m.itemList {
+		nameList = append(nameList, p.strName)
+	}
+	return json.Marshal(struct {
+		Source   string   `json:"source"`
+		GanZhi   string   `json:"ganZhi"`
+		Year     int      `json:"year"`
+		Relation string   `json:"relation"`
+		Target   string   `json:"target"`
+		Palace   string   `json:"palace,omitempty"`
+		Names    []string `json:"names"`
+	}{m.strSource, m.pGanZhi.String(), m.nYear, m.strRelation, m.strTarget, m.Palace(), nameList})
+}
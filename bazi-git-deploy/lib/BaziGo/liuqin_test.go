@@ -0,0 +1,96 @@
+package bazi
+
+import (
+	"strings"
+	"testing"
+)
+
+// liuQinText 六亲的简写, 柱 字 六亲
+func liuQinText(itemList []*TLiuQinItem) string {
+	var strList []string
+	for _, p := range itemList {
+		strList = append(strList, zhunamelist[p.Zhu()][:3]+p.Gan().String()+p.Name())
+	}
+	return strings.Join(strList, " ")
+}
+
+// TestLiuQinList 十神到六亲, 男女命不同
+func TestLiuQinList(t *testing.T) {
+	cases := []struct {
+		nSex     int
+		nShiShen int
+		strName  string
+		nWant    int
+	}{
+		{1, 5, "妻子", LiuQinPeiOu},
+		{1, 6, "儿子", LiuQinZiNv},
+		{1, 7, "女儿", LiuQinZiNv},
+		{1, 2, "孙女", LiuQinQiTa},
+		{0, 7, "丈夫", LiuQinPeiOu},
+		{0, 6, "偏夫", LiuQinPeiOu},
+		{0, 2, "女儿", LiuQinZiNv},
+		{0, 3, "儿子", LiuQinZiNv},
+		{0, 5, "婆婆", LiuQinQiTa},
+	}
+	for nSex := 0; nSex < 2; nSex++ {
+		// 父母兄弟男女命一样
+		if liuqincategorylist[nSex][4] != LiuQinFu || liuqincategorylist[nSex][9] != LiuQinMu || liuqincategorylist[nSex][0] != LiuQinXiongDi {
+			t.Errorf("性别%d 父母兄弟 = %v", nSex, liuqincategorylist[nSex])
+		}
+	}
+	for _, c := range cases {
+		if liuqinnamelist[c.nSex][c.nShiShen] != c.strName || liuqincategorylist[c.nSex][c.nShiShen] != c.nWant {
+			t.Errorf("性别%d %s = %s %s, want %s %s", c.nSex, GetShiShenLongFromNumber(c.nShiShen), liuqinnamelist[c.nSex][c.nShiShen],
+				GetLiuQinCategoryFromNumber(liuqincategorylist[c.nSex][c.nShiShen]), c.strName, GetLiuQinCategoryFromNumber(c.nWant))
+		}
+	}
+}
+
+// TestLiuQin 庚午 辛巳 壬午 甲辰 男命和女命, 壬水日主
+func TestLiuQin(t *testing.T) {
+	cases := []struct {
+		nSex      int
+		strPeiOu  string
+		strZiNv   string
+		strEvents string // 2020年庚子 冲两个午 和甲
+		strDaYun  string // 2020年所在的大运
+	}{
+		{1, "年丁妻子 日丁妻子", "年己女儿 月戊儿子 日己女儿 时戊儿子",
+			"2020年流年庚子冲年支午(祖上宫) 妻子 女儿\n2020年流年庚子冲日支午(夫妻宫) 妻子 女儿\n2020年流年庚子冲时干甲 孙女",
+			"2016年大运甲申冲年干庚 继母\n2016年大运甲申合月支巳(父母宫) 父亲 儿子 继母"},
+		{0, "年己丈夫 月戊偏夫 日己丈夫 时戊偏夫", "时甲女儿 时乙儿子",
+			"2020年流年庚子冲年支午(祖上宫) 婆婆 丈夫\n2020年流年庚子冲日支午(夫妻宫) 婆婆 丈夫\n2020年流年庚子冲时干甲 女儿",
+			""}, // 大运丁丑不冲合命局
+	}
+	for _, c := range cases {
+		pLiuQin := GetBazi(1990, 5, 17, 8, 0, 0, c.nSex).LiuQin()
+		if got := liuQinText(pLiuQin.Find(LiuQinPeiOu)); got != c.strPeiOu {
+			t.Errorf("性别%d 配偶 = %s, want %s", c.nSex, got, c.strPeiOu)
+		}
+		if got := liuQinText(pLiuQin.Find(LiuQinZiNv)); got != c.strZiNv {
+			t.Errorf("性别%d 子女 = %s, want %s", c.nSex, got, c.strZiNv)
+		}
+		// 父母男女命一样, 月柱的丙是父亲 辛是母亲
+		if got := liuQinText(append(pLiuQin.Find(LiuQinFu), pLiuQin.Find(LiuQinMu)...)); got != "月丙父亲 月辛母亲" {
+			t.Errorf("性别%d 父母 = %s", c.nSex, got)
+		}
+
+		var strList []string
+		for _, pEvent := range pLiuQin.LiuNianEvents(2020, 1) {
+			strList = append(strList, pEvent.String())
+		}
+		if got := strings.Join(strList, "\n"); got != c.strEvents {
+			t.Errorf("性别%d 2020 = %q, want %q", c.nSex, got, c.strEvents)
+		}
+
+		strList = nil
+		for _, pEvent := range pLiuQin.DaYunEvents(2020) {
+			if pEvent.Year() <= 2020 {
+				strList = append(strList, pEvent.String())
+			}
+		}
+		if got := strings.Join(strList, "\n"); got != c.strDaYun {
+			t.Errorf("性别%d 大运 = %q, want %q", c.nSex, got, c.strDaYun)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package bazi
+
+import "fmt"
+
+// 流月
+// 流年里的十二个月, 从立春开始, 每交一个节换一个月, 寅月到丑月.
+// 月干按五虎遁从年干推出: 甲己之年丙作首, 乙庚之岁戊为头, 丙辛必定寻庚起, 丁壬壬位顺行流, 戊癸何方发, 甲寅之上好追求.
+
+// NewLiuYue 新建流月, nYear 是八字年(立春换年), nMonth 是八字月 1-12, 1是寅月, 超出节气表范围时返回 nil
+func NewLiuYue(nYear int, nMonth int) *TLiuYue {
+	if nMonth < 1 || nMonth > 12 {
+		return nil
+	}
+	p := &TLiuYue{
+		nYear:  nYear,
+		nMonth: nMonth,
+	}
+	return p.init()
+}
+
+// NewLiuYueFromSolarDate 某个新历时间所在的流月
+func NewLiuYueFromSolarDate(pSolarDate *TSolarDate) *TLiuYue {
+	pBaziDate := pSolarDate.ToBaziDate()
+	return NewLiuYue(pBaziDate.Year(), pBaziDate.Month())
+}
+
+// GetLiuYueList 某个八字年的十二个流月, 寅月到丑月
+func GetLiuYueList(nYear int) []*TLiuYue {
+	var result []*TLiuYue
+	for nMonth := 1; nMonth <= 12; nMonth++ {
+		if p := NewLiuYue(nYear, nMonth); p != nil {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// TLiuYue 流月
+type TLiuYue struct {
+	nYear     int         // 八字年
+	nMonth    int         // 八字月 1-12
+	pGanZhi   *TGanZhi    // 月干支
+	pStartJie *TJieQiDate // 交节时间, 本月开始
+	pEndJie   *TJieQiDate // 下一个节, 本月结束
+}
+
+func (m *TLiuYue) init() *TLiuYue {
+	// 节气表里立春是0, 每月的节隔两个, 丑月的小寒在下一个新历年
+	m.pStartJie = getLiuYueJie(m.nYear, m.nMonth)
+	m.pEndJie = getLiuYueJie(m.nYear, m.nMonth+1)
+	if m.pStartJie == nil || m.pEndJie == nil {
+		return nil
+	}
+
+	// 五虎遁, 寅月的天干
+	nYearGan := NewGanZhiFromYear(m.nYear).Value() % 10
+	nGan := (nYearGan%5*2 + 2 + m.nMonth - 1) % 10
+	nZhi := (m.nMonth + 1) % 12
+	m.pGanZhi = CombineGanZhi(NewGan(nGan), NewZhi(nZhi))
+	return m
+}
+
+// getLiuYueJie 八字年某月的节, nMonth 为13时是下一年的立春
+func getLiuYueJie(nYear int, nMonth int) *TJieQiDate {
+	switch {
+	case nMonth == 13:
+		return getJieQiDateOfYear(nYear+1, 0)
+	case nMonth == 12:
+		return getJieQiDateOfYear(nYear+1, 22)
+	}
+	return getJieQiDateOfYear(nYear, (nMonth-1)*2)
+}
+
+// Year 八字年
+func (m *TLiuYue) Year() int {
+	return m.nYear
+}
+
+// Month 八字月 1-12, 1是寅月
+func (m *TLiuYue) Month() int {
+	return m.nMonth
+}
+
+// GanZhi 月干支
+func (m *TLiuYue) GanZhi() *TGanZhi {
+	return m.pGanZhi
+}
+
+// Gan 月干
+func (m *TLiuYue) Gan() *TGan {
+	pGan, _ := m.pGanZhi.ExtractGanZhi()
+	return pGan
+}
+
+// Zhi 月支
+func (m *TLiuYue) Zhi() *TZhi {
+	_, pZhi := m.pGanZhi.ExtractGanZhi()
+	return pZhi
+}
+
+// Jie 本月的节
+func (m *TLiuYue) Jie() *TJieQiDate {
+	return m.pStartJie
+}
+
+// StartDate 本月开始, 交节的时间
+func (m *TLiuYue) StartDate() *TSolarDate {
+	return m.pStartJie.ToSolarDate()
+}
+
+// EndDate 本月结束, 下一个节交节的时间
+func (m *TLiuYue) EndDate() *TSolarDate {
+	return m.pEndJie.ToSolarDate()
+}
+
+// String 打印用
+func (m *TLiuYue) String() string {
+	return fmt.Sprintf("%v月 %v %v", m.pGanZhi, m.pStartJie.JieQi.String(), m.StartDate())
+}
@@ -201,8 +201,8 @@ func (m *TLunarDate) genNormal() {
 	}
 
 	// 有闰月
-	// 闰月之前不变
-	if m.nMonth < m.nLeapMonth {
+	// 闰月之前不变, 闰几月的前一个就是正常的几月
+	if m.nMonth <= m.nLeapMonth {
 		m.nConventionalMonth = m.nMonth
 		return
 	}
@@ -943,7 +943,7 @@ func (m *TLunarDate) Year() string {
 // Month 月
 func (m *TLunarDate) Month() string {
 	strResult := ""
-	if m.nConventionalMonth == m.nMonth-1 {
+	if m.isLeap {
 		strResult += "闰"
 	}
 
@@ -1,5 +1,7 @@
 package bazi
 
+import "encoding/json"
+
 //  {* 纳音五行，与相邻一对六十干支对应}
 // 甲子乙丑海中金 丙寅丁卯炉中火 戊辰己巳大林木
 // 庚午辛未路旁土 壬申癸酉剑锋金 甲戌乙亥山头火
@@ -12,6 +14,41 @@ package bazi
 // 壬子癸丑桑柘木 甲寅乙卯大溪水 丙辰丁巳砂中土
 // 戊午己未天上火 庚申辛酉石榴木 壬戌癸亥大海水
 
+// 纳音五行, 0-4 对应 金木水火土
+var nayinwuxinglist = [30]int{
+	0, 3, 1, // 海中金 炉中火 大林木
+	4, 0, 3, // 路旁土 剑锋金 山头火
+	2, 4, 0, // 涧下水 城墙土 白蜡金
+	1, 2, 4, // 杨柳木 泉中水 屋上土
+	3, 1, 2, // 霹雷火 松柏木 长流水
+	0, 3, 1, // 沙中金 山下火 平地木
+	4, 0, 3, // 壁上土 金箔金 佛灯火
+	2, 4, 0, // 天河水 大驿土 钗钏金
+	1, 2, 4, // 桑柘木 大溪水 沙中土
+	3, 1, 2, // 天上火 石榴木 大海水
+}
+
+// 纳音受克时的特性
+const (
+	NaYinKePu    = iota // 普通, 受克为凶
+	NaYinKeXi           // 受克反成, 比如 剑锋金 沙中金 得火而成器
+	NaYinKeBuPa         // 不怕克, 天河水 大海水 土不能克
+)
+
+// 纳音受克时的特性, 三命通会: 金赖火成, 火得水而福, 土得木而秀, 木得金而荣, 惟天河 大海之水土不能克
+var nayinkelist = [30]int{
+	0, 0, 0, // 海中金 炉中火 大林木
+	1, 1, 0, // 路旁土 剑锋金 山头火
+	0, 0, 0, // 涧下水 城墙土 白蜡金
+	0, 0, 0, // 杨柳木 泉中水 屋上土
+	1, 0, 0, // 霹雷火 松柏木 长流水
+	1, 0, 1, // 沙中金 山下火 平地木
+	0, 0, 0, // 壁上土 金箔金 佛灯火
+	2, 1, 0, // 天河水 大驿土 钗钏金
+	0, 0, 1, // 桑柘木 大溪水 沙中土
+	1, 0, 2, // 天上火 石榴木 大海水
+}
+
 // GetNaYinFromNumber 从数字获得纳音名, 0-29
 func GetNaYinFromNumber(nValue int) string {
 	switch nValue {
@@ -117,3 +154,41 @@ func (m *TNaYin) String() string {
 func (m *TNaYin) ToString() string {
 	return m.String()
 }
+
+// ToWuXing 纳音五行
+func (m *TNaYin) ToWuXing() *TWuXing {
+	return NewWuXing(nayinwuxinglist[m.Value()])
+}
+
+// GanZhiList 纳这个音的两个干支, 比如 海中金 是 甲子 乙丑
+func (m *TNaYin) GanZhiList() [2]*TGanZhi {
+	return [2]*TGanZhi{NewGanZhi(m.Value() * 2), NewGanZhi(m.Value()*2 + 1)}
+}
+
+// KeType 受克时的特性 NaYinKePu NaYinKeXi NaYinKeBuPa
+func (m *TNaYin) KeType() int {
+	return nayinkelist[m.Value()]
+}
+
+// KeDesc 受克时的说明, 普通的为空
+func (m *TNaYin) KeDesc() string {
+	switch m.KeType() {
+	case NaYinKeXi:
+		// 克我的五行是生我的五行的母
+		return m.String() + "得" + m.ToWuXing().Mother().Mother().String() + "而成"
+	case NaYinKeBuPa:
+		return m.String() + "土不能克"
+	}
+	return ""
+}
+
+// MarshalJSON JSON 序列化
+func (m *TNaYin) MarshalJSON() ([]byte, error) {
+	pList := m.GanZhiList()
+	return json.Marshal(struct {
+		Name   string   `json:"name"`
+		WuXing string   `json:"wuXing"`
+		GanZhi []string `json:"ganZhi"`
+		Ke     string   `json:"ke,omitempty"`
+	}{m.String(), m.ToWuXing().String(), []string{pList[0].String(), pList[1].String()}, m.KeDesc()})
+}
@@ -0,0 +1,88 @@
+package bazi
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNaYinKeType 金赖火成, 火得水而福, 土得木而秀, 木得金而荣, 惟天河 大海之水土不能克
+func TestNaYinKeType(t *testing.T) {
+	var xiList, buPaList []string
+	for i := 0; i < 30; i++ {
+		pNaYin := NewNaYin(i)
+		switch pNaYin.KeType() {
+		case NaYinKeXi:
+			xiList = append(xiList, pNaYin.String())
+		case NaYinKeBuPa:
+			buPaList = append(buPaList, pNaYin.String())
+		}
+	}
+	if got := strings.Join(xiList, " "); got != "路旁土 剑锋金 霹雷火 沙中金 平地木 大驿土 沙中土 天上火" {
+		t.Errorf("受克反成 = %s", got)
+	}
+	if got := strings.Join(buPaList, " "); got != "天河水 大海水" {
+		t.Errorf("不怕克 = %s", got)
+	}
+
+	cases := []struct {
+		nNaYin  int
+		strWant string
+	}{
+		{4, "剑锋金得火而成"},
+		{12, "霹雷火得水而成"},
+		{17, "平地木得金而成"},
+		{22, "大驿土得木而成"},
+		{21, "天河水土不能克"},
+		{0, ""},
+	}
+	for _, c := range cases {
+		if got := NewNaYin(c.nNaYin).KeDesc(); got != c.strWant {
+			t.Errorf("%v KeDesc = %s, want %s", NewNaYin(c.nNaYin), got, c.strWant)
+		}
+	}
+}
+
+// TestNaYinGuanXi 从自己这一方看纳音生克和吉凶
+func TestNaYinGuanXi(t *testing.T) {
+	cases := []struct {
+		nSelf, nOther int
+		strWant       string
+		nJiXiong      int
+	}{
+		{0, 8, "比和: 甲海中金与乙白蜡金比和", 1},
+		{0, 3, "生我: 乙路旁土生甲海中金", 1},
+		{0, 6, "我生: 甲海中金生乙涧下水", 0},
+		{0, 2, "我克: 甲海中金克乙大林木", 0},
+		{2, 0, "克我: 乙海中金克甲大林木", -1},
+		// 受克反成, 克我也是吉
+		{4, 1, "克我: 乙炉中火克甲剑锋金, 剑锋金得火而成", 1},
+		{1, 4, "我克: 甲炉中火克乙剑锋金, 剑锋金得火而成", 0},
+		// 土不能克
+		{21, 3, "克我: 乙路旁土克甲天河水, 天河水土不能克", 0},
+	}
+	for _, c := range cases {
+		pGuanXi := NewNaYinGuanXi(NewNaYin(c.nSelf), "甲", NewNaYin(c.nOther), "乙")
+		if pGuanXi.String() != c.strWant || pGuanXi.JiXiong() != c.nJiXiong {
+			t.Errorf("%v %v = %v %d, want %s %d", NewNaYin(c.nSelf), NewNaYin(c.nOther), pGuanXi, pGuanXi.JiXiong(), c.strWant, c.nJiXiong)
+		}
+		if pGuanXi.IsKe() != strings.Contains(c.strWant, "克") || pGuanXi.IsSheng() != strings.Contains(c.strWant, "生") {
+			t.Errorf("%v IsKe = %v IsSheng = %v", pGuanXi, pGuanXi.IsKe(), pGuanXi.IsSheng())
+		}
+	}
+}
+
+// TestNaYinSiZhu 庚午 辛巳 壬午 甲辰, 年柱路旁土 日柱杨柳木
+func TestNaYinSiZhu(t *testing.T) {
+	pBazi := GetBazi(1990, 5, 17, 8, 0, 0, 1)
+	if got := pBazi.SiZhu().NaYinGuanXi(); got.String() != "克我: 日柱杨柳木克年柱路旁土, 路旁土得木而成" || got.JiXiong() != 1 {
+		t.Errorf("NaYinGuanXi = %v %d", got, got.JiXiong())
+	}
+	// 2024年甲辰覆灯火
+	var strList []string
+	for _, p := range pBazi.LiuNianNaYin(2024) {
+		strList = append(strList, p.String())
+	}
+	if got := strings.Join(strList, "\n"); got != "生我: 流年佛灯火生年柱路旁土\n我生: 日柱杨柳木生流年佛灯火" {
+		t.Errorf("LiuNianNaYin(2024) = %q", got)
+	}
+}
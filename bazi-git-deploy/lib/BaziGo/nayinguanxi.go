@@ -0,0 +1,183 @@
+package bazi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 纳音生克
+// 纳音论命以年柱纳音为本命, 日柱纳音为身, 行运的纳音和本命 日柱比较.
+// 生我比和为吉, 我生泄气 我克耗力为平, 克我为凶.
+// 受克也有例外: 剑锋金 沙中金 得火而成, 霹雷火 天上火 得水而福, 路旁土 大驿土 沙中土 得木而秀,
+// 平地木 得金而荣, 这些受克反成; 天河水 大海水 土不能克, 受克无妨.
+
+// 纳音关系的名字, 从自己这一方看
+const (
+	NaYinBiHe    = "比和"
+	NaYinShengWo = "生我"
+	NaYinWoSheng = "我生"
+	NaYinWoKe    = "我克"
+	NaYinKeWo    = "克我"
+)
+
+// TNaYinGuanXi 两个纳音之间的生克
+type TNaYinGuanXi struct {
+	pSelf    *TNaYin
+	pOther   *TNaYin
+	strSelf  string // 年柱 日柱
+	strOther string // 日柱 大运 流年 对方年柱
+	strName  string // 比和 生我 我生 我克 克我
+	strKe    string // 受克的一方是受克反成或不怕克时的说明
+	nJiXiong int    // 1吉 0平 -1凶, 从自己这一方看
+}
+
+// NewNaYinGuanXi 从 pSelf 这一方看和 pOther 的纳音生克
+func NewNaYinGuanXi(pSelf *TNaYin, strSelf string, pOther *TNaYin, strOther string) *TNaYinGuanXi {
+	p := &TNaYinGuanXi{pSelf: pSelf, pOther: pOther, strSelf: strSelf, strOther: strOther}
+	p.init()
+	return p
+}
+
+func (m *TNaYinGuanXi) init() {
+	// 受克的一方
+	var pKe *TNaYin
+	switch m.pSelf.ToWuXing().Relation(m.pOther.ToWuXing()) {
+	case WuXingTong:
+		m.strName, m.nJiXiong = NaYinBiHe, 1
+	case WuXingBeiSheng:
+		m.strName, m.nJiXiong = NaYinShengWo, 1
+	case WuXingSheng:
+		m.strName, m.nJiXiong = NaYinWoSheng, 0
+	case WuXingKe:
+		m.strName, m.nJiXiong = NaYinWoKe, 0
+		pKe = m.pOther
+	case WuXingBeiKe:
+		m.strName, m.nJiXiong = NaYinKeWo, -1
+		pKe = m.pSelf
+	}
+	if pKe == nil {
+		return
+	}
+
+	m.strKe = pKe.KeDesc()
+	if m.strName != NaYinKeWo {
+		return
+	}
+	switch pKe.KeType() {
+	case NaYinKeXi:
+		m.nJiXiong = 1
+	case NaYinKeBuPa:
+		m.nJiXiong = 0
+	}
+}
+
+// Self 自己这一方的纳音
+func (m *TNaYinGuanXi) Self() *TNaYin {
+	return m.pSelf
+}
+
+// Other 对方的纳音
+func (m *TNaYinGuanXi) Other() *TNaYin {
+	return m.pOther
+}
+
+// Name 比和 生我 我生 我克 克我
+func (m *TNaYinGuanXi) Name() string {
+	return m.strName
+}
+
+// IsKe 两个纳音相克, 不论方向
+func (m *TNaYinGuanXi) IsKe() bool {
+	return m.strName == NaYinWoKe || m.strName == NaYinKeWo
+}
+
+// IsSheng 两个纳音相生, 不论方向
+func (m *TNaYinGuanXi) IsSheng() bool {
+	return m.strName == NaYinShengWo || m.strName == NaYinWoSheng
+}
+
+// KeType 相克时受克一方的特性 NaYinKePu NaYinKeXi NaYinKeBuPa, 不相克时为 NaYinKePu
+func (m *TNaYinGuanXi) KeType() int {
+	switch m.strName {
+	case NaYinWoKe:
+		return m.pOther.KeType()
+	case NaYinKeWo:
+		return m.pSelf.KeType()
+	}
+	return NaYinKePu
+}
+
+// JiXiong 1吉 0平 -1凶, 从自己这一方看
+func (m *TNaYinGuanXi) JiXiong() int {
+	return m.nJiXiong
+}
+
+// Desc 说明, 比如 年柱海中金克日柱大林木
+func (m *TNaYinGuanXi) Desc() string {
+	strDesc := ""
+	switch m.strName {
+	case NaYinBiHe:
+		strDesc = fmt.Sprintf("%s%v与%s%v比和", m.strSelf, m.pSelf, m.strOther, m.pOther)
+	case NaYinShengWo:
+		strDesc = fmt.Sprintf("%s%v生%s%v", m.strOther, m.pOther, m.strSelf, m.pSelf)
+	case NaYinWoSheng:
+		strDesc = fmt.Sprintf("%s%v生%s%v", m.strSelf, m.pSelf, m.strOther, m.pOther)
+	case NaYinWoKe:
+		strDesc = fmt.Sprintf("%s%v克%s%v", m.strSelf, m.pSelf, m.strOther, m.pOther)
+	case NaYinKeWo:
+		strDesc = fmt.Sprintf("%s%v克%s%v", m.strOther, m.pOther, m.strSelf, m.pSelf)
+	}
+	if m.strKe != "" {
+		strDesc += ", " + m.strKe
+	}
+	return strDesc
+}
+
+// String 打印用
+func (m *TNaYinGuanXi) String() string {
+	return m.strName + ": " + m.Desc()
+}
+
+// MarshalJSON JSON 序列化
+func (m *TNaYinGuanXi) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Self    string `json:"self"`
+		Other   string `json:"other"`
+		Name    string `json:"name"`
+		JiXiong int    `json:"jiXiong"`
+		Desc    string `json:"desc"`
+	}{m.strSelf + m.pSelf.String(), m.strOther + m.pOther.String(), m.strName, m.nJiXiong, m.Desc()})
+}
+
+// NaYinList 四柱的纳音
+func (m *TSiZhu) NaYinList() [4]*TNaYin {
+	return [4]*TNaYin{
+		m.pYearZhu.GanZhi().ToNaYin(),
+		m.pMonthZhu.GanZhi().ToNaYin(),
+		m.pDayZhu.GanZhi().ToNaYin(),
+		m.pHourZhu.GanZhi().ToNaYin(),
+	}
+}
+
+// NaYinGuanXi 年柱(本命)纳音和日柱纳音的生克
+func (m *TSiZhu) NaYinGuanXi() *TNaYinGuanXi {
+	return NewNaYinGuanXi(m.pYearZhu.GanZhi().ToNaYin(), "年柱", m.pDayZhu.GanZhi().ToNaYin(), "日柱")
+}
+
+// naYinXingYun 行运纳音和年柱 日柱纳音的生克
+func (m *TSiZhu) naYinXingYun(pGanZhi *TGanZhi, strSource string) []*TNaYinGuanXi {
+	return []*TNaYinGuanXi{
+		NewNaYinGuanXi(m.pYearZhu.GanZhi().ToNaYin(), "年柱", pGanZhi.ToNaYin(), strSource),
+		NewNaYinGuanXi(m.pDayZhu.GanZhi().ToNaYin(), "日柱", pGanZhi.ToNaYin(), strSource),
+	}
+}
+
+// DaYunNaYin 第几步大运的纳音和年柱 日柱纳音的生克
+func (m *TBazi) DaYunNaYin(nIndex int) []*TNaYinGuanXi {
+	return m.pSiZhu.naYinXingYun(m.pDaYun.Zhu(nIndex).GanZhi(), "大运")
+}
+
+// LiuNianNaYin 某一年流年的纳音和年柱 日柱纳音的生克
+func (m *TBazi) LiuNianNaYin(nYear int) []*TNaYinGuanXi {
+	return m.pSiZhu.naYinXingYun(NewGanZhiFromYear(nYear), "流年")
+}
@@ -0,0 +1,202 @@
+package bazi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 十神统计
+// 把四柱天干和地支藏干的十神放在一起数: 天干出现叫透, 藏干里有叫藏(有根), 两样都没有就是缺.
+// 强度用 ShiShenWeightList, 天干算100, 藏干按本气 中气 余气的系数.
+
+// NewShiShenTongJi 统计四柱的十神
+func NewShiShenTongJi(pSiZhu *TSiZhu) *TShiShenTongJi {
+	p := &TShiShenTongJi{}
+	p.init(pSiZhu)
+	return p
+}
+
+// TShiShenTongJi 十神统计
+type TShiShenTongJi struct {
+	itemList [10]*TShiShenTongJiItem // 下标是十神 0-9
+}
+
+// TShiShenTongJiItem 一个十神的统计
+type TShiShenTongJiItem struct {
+	pShiShen *TShiShen
+	pWuXing  *TWuXing // 这个十神的五行
+	nTou     int      // 天干透出几个, 日干不算
+	nCang    int      // 藏干里有几个
+	nWeight  int      // 强度
+}
+
+func (m *TShiShenTongJi) init(pSiZhu *TSiZhu) {
+	pDayGan := pSiZhu.DayZhu().Gan()
+	for i := range m.itemList {
+		m.itemList[i] = &TShiShenTongJiItem{
+			pShiShen: NewShiShen(i),
+			pWuXing:  shiShenWuXing(pDayGan, i),
+		}
+	}
+
+	for _, pZhu := range []*TZhu{pSiZhu.YearZhu(), pSiZhu.MonthZhu(), pSiZhu.DayZhu(), pSiZhu.HourZhu()} {
+		if pZhu != pSiZhu.DayZhu() {
+			m.itemList[pZhu.ShiShen().Value()].nTou++
+		}
+		pCangGan := pZhu.CangGan()
+		for i := 0; i < pCangGan.Size(); i++ {
+			m.itemList[pCangGan.ShiShen(i).Value()].nCang++
+		}
+	}
+
+	for i, nWeight := range pSiZhu.ShiShenWeightList() {
+		m.itemList[i].nWeight = nWeight
+	}
+}
+
+// shiShenWuXing 十神的五行, 比劫同我, 食伤我生, 财我克, 官杀克我, 印生我
+func shiShenWuXing(pDayGan *TGan, nShiShen int) *TWuXing {
+	pWuXing := pDayGan.ToWuXing()
+	switch nShiShen / 2 {
+	case 1:
+		return pWuXing.Child()
+	case 2:
+		return pWuXing.Child().Child()
+	case 3:
+		return pWuXing.Mother().Mother()
+	case 4:
+		return pWuXing.Mother()
+	}
+	return pWuXing
+}
+
+// ItemList 十个十神的统计, 下标是十神
+func (m *TShiShenTongJi) ItemList() [10]*TShiShenTongJiItem {
+	return m.itemList
+}
+
+// Item 某个十神的统计
+func (m *TShiShenTongJi) Item(pShiShen *TShiShen) *TShiShenTongJiItem {
+	return m.itemList[pShiShen.Value()]
+}
+
+// Missing 四柱里完全没有的十神
+func (m *TShiShenTongJi) Missing() []*TShiShen {
+	result := []*TShiShen{}
+	for _, item := range m.itemList {
+		if item.IsMissing() {
+			result = append(result, item.pShiShen)
+		}
+	}
+	return result
+}
+
+// Strongest 强度最高的十神, 一样高时取前面的
+func (m *TShiShenTongJi) Strongest() *TShiShen {
+	pResult := m.itemList[0]
+	for _, item := range m.itemList {
+		if item.nWeight > pResult.nWeight {
+			pResult = item
+		}
+	}
+	return pResult.pShiShen
+}
+
+// MaxWeight 最高的强度, 画图用
+func (m *TShiShenTongJi) MaxWeight() int {
+	return m.Item(m.Strongest()).nWeight
+}
+
+// String 打印用
+func (m *TShiShenTongJi) String() string {
+	strResult := "十神统计:\n"
+	for _, item := range m.itemList {
+		strResult += item.String() + "\n"
+	}
+	var strList []string
+	for _, pShiShen := range m.Missing() {
+		strList = append(strList, GetShiShenLongFromNumber(pShiShen.Value()))
+	}
+	if len(strList) > 0 {
+		strResult += "缺: " + strings.Join(strList, " ") + "\n"
+	}
+	return strResult
+}
+
+// MarshalJSON JSON 序列化
+func (m *TShiShenTongJi) MarshalJSON() ([]byte, error) {
+	missingList := []string{}
+	for _, pShiShen := range m.Missing() {
+		missingList = append(missingList, GetShiShenLongFromNumber(pShiShen.Value()))
+	}
+	return json.Marshal(struct {
+		Items     [10]*TShiShenTongJiItem `json:"items"`
+		Missing   []string                `json:"missing"`
+		Strongest string                  `json:"strongest"`
+	}{m.itemList, missingList, GetShiShenLongFromNumber(m.Strongest().Value())})
+}
+
+// ShiShen 十神
+func (m *TShiShenTongJiItem) ShiShen() *TShiShen {
+	return m.pShiShen
+}
+
+// WuXing 这个十神的五行
+func (m *TShiShenTongJiItem) WuXing() *TWuXing {
+	return m.pWuXing
+}
+
+// Tou 天干透出几个, 日干不算
+func (m *TShiShenTongJiItem) Tou() int {
+	return m.nTou
+}
+
+// Cang 藏干里有几个
+func (m *TShiShenTongJiItem) Cang() int {
+	return m.nCang
+}
+
+// Count 一共几个
+func (m *TShiShenTongJiItem) Count() int {
+	return m.nTou + m.nCang
+}
+
+// Weight 强度, 天干100, 藏干按系数
+func (m *TShiShenTongJiItem) Weight() int {
+	return m.nWeight
+}
+
+// IsTou 天干透出
+func (m *TShiShenTongJiItem) IsTou() bool {
+	return m.nTou > 0
+}
+
+// IsCang 藏干里有, 有根
+func (m *TShiShenTongJiItem) IsCang() bool {
+	return m.nCang > 0
+}
+
+// IsMissing 四柱里完全没有
+func (m *TShiShenTongJiItem) IsMissing() bool {
+	return m.Count() == 0
+}
+
+// String 打印用
+func (m *TShiShenTongJiItem) String() string {
+	return fmt.Sprintf("%s(%v) 透%d 藏%d 强度%d", GetShiShenLongFromNumber(m.pShiShen.Value()), m.pWuXing, m.nTou, m.nCang, m.nWeight)
+}
+
+// MarshalJSON JSON 序列化
+func (m *TShiShenTongJiItem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ShiShen string `json:"shiShen"`
+		WuXing  string `json:"wuXing"`
+		Tou     int    `json:"tou"`
+		Cang    int    `json:"cang"`
+		Weight  int    `json:"weight"`
+		IsTou   bool   `json:"isTou"`
+		IsCang  bool   `json:"isCang"`
+		Missing bool   `json:"missing"`
+	}{GetShiShenLongFromNumber(m.pShiShen.Value()), m.pWuXing.String(), m.nTou, m.nCang, m.nWeight, m.IsTou(), m.IsCang(), m.IsMissing()})
+}
@@ -0,0 +1,65 @@
+package bazi
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestShiShenWuXing 十神的五行按日干推
+func TestShiShenWuXing(t *testing.T) {
+	cases := []struct {
+		nDayGan int
+		strWant string // 比肩到正印
+	}{
+		{0, "木木火火土土金金水水"}, // 甲
+		{8, "水水木木火火土土金金"}, // 壬
+		{5, "土土金金水水木木火火"}, // 己
+	}
+	for _, c := range cases {
+		strGot := ""
+		for i := 0; i < 10; i++ {
+			strGot += shiShenWuXing(NewGan(c.nDayGan), i).String()
+		}
+		if strGot != c.strWant {
+			t.Errorf("%v日 = %s, want %s", NewGan(c.nDayGan), strGot, c.strWant)
+		}
+	}
+}
+
+// TestShiShenTongJi 庚午 辛巳 壬午 甲辰, 壬水日主
+// 天干 庚偏印 辛正印 甲食神; 午藏 丁正财70 己正官30 两个, 巳藏 丙偏财60 庚偏印30 戊七杀10, 辰藏 戊七杀60 癸劫财30 乙伤官10
+func TestShiShenTongJi(t *testing.T) {
+	pTongJi := GetBazi(1990, 5, 17, 8, 0, 0, 1).SiZhu().ShiShenTongJi()
+	wantList := []string{
+		"比肩(水) 透0 藏0 强度0",
+		"劫财(水) 透0 藏1 强度30",
+		"食神(木) 透1 藏0 强度100",
+		"伤官(木) 透0 藏1 强度10",
+		"偏财(火) 透0 藏1 强度60",
+		"正财(火) 透0 藏2 强度140",
+		"七杀(土) 透0 藏2 强度70",
+		"正官(土) 透0 藏2 强度60",
+		"偏印(金) 透1 藏1 强度130",
+		"正印(金) 透1 藏0 强度100",
+	}
+	for i, item := range pTongJi.ItemList() {
+		if item.String() != wantList[i] {
+			t.Errorf("ItemList[%d] = %s, want %s", i, item, wantList[i])
+		}
+	}
+
+	var strList []string
+	for _, pShiShen := range pTongJi.Missing() {
+		strList = append(strList, GetShiShenLongFromNumber(pShiShen.Value()))
+	}
+	if got := strings.Join(strList, " "); got != "比肩" {
+		t.Errorf("Missing = %s, want 比肩", got)
+	}
+	if got := pTongJi.Strongest().Value(); got != 5 || pTongJi.MaxWeight() != 140 {
+		t.Errorf("Strongest = %s %d, want 正财 140", GetShiShenLongFromNumber(got), pTongJi.MaxWeight())
+	}
+	pItem := pTongJi.Item(NewShiShen(8))
+	if !pItem.IsTou() || !pItem.IsCang() || pItem.Count() != 2 {
+		t.Errorf("偏印 = %v", pItem)
+	}
+}
@@ -0,0 +1,181 @@
+package bazi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 旺相休囚死 和 人元司令
+// 五行在月令的状态: 当令者旺, 令生者相, 生令者休, 克令者囚, 令克者死. 月令五行按月支, 辰戌丑未四个月土旺.
+// 人元司令分野: 交节之后月支藏干按天数轮流当令, 比如 寅月 立春后 戊土七日 丙火七日 甲木十六日.
+
+// 旺相休囚死
+const (
+	WangShuaiWang  = iota // 旺
+	WangShuaiXiang        // 相
+	WangShuaiXiu          // 休
+	WangShuaiQiu          // 囚
+	WangShuaiSi           // 死
+)
+
+// GetWangShuaiFromNumber 从数字获得旺相休囚死, 0-4
+func GetWangShuaiFromNumber(nValue int) string {
+	switch nValue {
+	case WangShuaiWang:
+		return "旺"
+	case WangShuaiXiang:
+		return "相"
+	case WangShuaiXiu:
+		return "休"
+	case WangShuaiQiu:
+		return "囚"
+	case WangShuaiSi:
+		return "死"
+	}
+	return ""
+}
+
+// 旺相休囚死对五行强度的系数(百分比), 只在喜用神按季节加权时用
+var wangshuaiweightlist = [5]int{120, 110, 100, 90, 80}
+
+// 司令的天干五行再加的系数(百分比)
+const siLingWeight = 110
+
+// TWangShuai 旺相休囚死
+type TWangShuai int
+
+// NewWangShuai 五行在某个月令的旺相休囚死
+func NewWangShuai(pMonthZhi *TZhi, pWuXing *TWuXing) *TWangShuai {
+	var nValue int
+	switch pMonthZhi.ToWuXing().Relation(pWuXing) {
+	case WuXingTong:
+		nValue = WangShuaiWang // 当令
+	case WuXingSheng:
+		nValue = WangShuaiXiang // 令生
+	case WuXingBeiSheng:
+		nValue = WangShuaiXiu // 生令
+	case WuXingBeiKe:
+		nValue = WangShuaiQiu // 克令
+	case WuXingKe:
+		nValue = WangShuaiSi // 令克
+	}
+	wangshuai := TWangShuai(nValue)
+	return &wangshuai
+}
+
+// Value 转换成int
+func (m *TWangShuai) Value() int {
+	return (int)(*m)
+}
+
+// String 转换成可阅读的字符串
+func (m *TWangShuai) String() string {
+	return GetWangShuaiFromNumber(m.Value())
+}
+
+// MarshalText 文本序列化, 输出 旺 相 休 囚 死
+func (m *TWangShuai) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// 人元司令分野, 下标是地支, 交节后依次当令的天干, -1 表示没有
+var silingganlist = [12][3]int{
+	{8, 9, -1}, // 子 壬水十日 癸水二十日
+	{9, 7, 5},  // 丑 癸水九日 辛金三日 己土十八日
+	{4, 2, 0},  // 寅 戊土七日 丙火七日 甲木十六日
+	{0, 1, -1}, // 卯 甲木十日 乙木二十日
+	{1, 9, 4},  // 辰 乙木九日 癸水三日 戊土十八日
+	{4, 6, 2},  // 巳 戊土五日 庚金九日 丙火十六日
+	{2, 5, 3},  // 午 丙火十日 己土九日 丁火十一日
+	{3, 1, 5},  // 未 丁火九日 乙木三日 己土十八日
+	{4, 8, 6},  // 申 戊土十日 壬水三日 庚金十七日
+	{6, 7, -1}, // 酉 庚金十日 辛金二十日
+	{7, 3, 4},  // 戌 辛金九日 丁火三日 戊土十八日
+	{4, 0, 8}}  // 亥 戊土七日 甲木五日 壬水十八日
+
+// 人元司令的天数, 和上表对应, 最后一个天干一直管到下一个节
+var silingdayslist = [12][3]int{
+	{10, 20, 0},
+	{9, 3, 18},
+	{7, 7, 16},
+	{10, 20, 0},
+	{9, 3, 18},
+	{5, 9, 16},
+	{10, 9, 11},
+	{9, 3, 18},
+	{10, 3, 17},
+	{10, 20, 0},
+	{9, 3, 18},
+	{7, 5, 18}}
+
+// NewSiLing 人元司令, 按出生时间离上一个节的天数查表
+func NewSiLing(pBaziDate *TBaziDate, pSolarDate *TSolarDate) *TSiLing {
+	p := &TSiLing{}
+	p.init(pBaziDate, pSolarDate)
+	return p
+}
+
+// TSiLing 人元司令
+type TSiLing struct {
+	pMonthZhi *TZhi   // 月支
+	pJieQi    *TJieQi // 上一个节
+	pGan      *TGan   // 司令的天干
+	nDays     int     // 交节后第几天, 交节当天是第1天
+}
+
+func (m *TSiLing) init(pBaziDate *TBaziDate, pSolarDate *TSolarDate) {
+	// 八字月1是寅月
+	m.pMonthZhi = NewZhi((pBaziDate.Month() + 1) % 12)
+	pJie := pBaziDate.PreviousJie()
+	m.pJieQi = &pJie.JieQi
+
+	// 按整天算, 交节不足一天算第1天
+	nSeconds := pJie.ToSolarDate().GetDiffSeconds(pSolarDate)
+	m.nDays = int(nSeconds/(24*60*60)) + 1
+
+	nZhi := m.pMonthZhi.Value()
+	nPassed := 0
+	for i := 0; i < 3 && silingganlist[nZhi][i] >= 0; i++ {
+		m.pGan = NewGan(silingganlist[nZhi][i])
+		nPassed += silingdayslist[nZhi][i]
+		if m.nDays <= nPassed {
+			break
+		}
+	}
+}
+
+// MonthZhi 月支
+func (m *TSiLing) MonthZhi() *TZhi {
+	return m.pMonthZhi
+}
+
+// JieQi 上一个节
+func (m *TSiLing) JieQi() *TJieQi {
+	return m.pJieQi
+}
+
+// Gan 司令的天干
+func (m *TSiLing) Gan() *TGan {
+	return m.pGan
+}
+
+// Days 交节后第几天
+func (m *TSiLing) Days() int {
+	return m.nDays
+}
+
+// String 打印用
+func (m *TSiLing) String() string {
+	return fmt.Sprintf("%v月 %v后第%d天 %v%v司令", m.pMonthZhi, m.pJieQi, m.nDays, m.pGan, m.pGan.ToWuXing())
+}
+
+// MarshalJSON JSON 序列化
+func (m *TSiLing) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		MonthZhi string `json:"monthZhi"`
+		JieQi    string `json:"jieQi"`
+		Days     int    `json:"days"`
+		Gan      string `json:"gan"`
+		WuXing   string `json:"wuXing"`
+	}{m.pMonthZhi.String(), m.pJieQi.String(), m.nDays, m.pGan.String(), m.pGan.ToWuXing().String()})
+}
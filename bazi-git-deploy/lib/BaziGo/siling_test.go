@@ -0,0 +1,77 @@
+package bazi
+
+import "testing"
+
+// TestWangShuai 十二个月令下金木水火土的旺相休囚死
+func TestWangShuai(t *testing.T) {
+	// 按金木水火土的顺序
+	wantList := [12]string{
+		"休相旺死囚", // 子 水
+		"相囚死休旺", // 丑 土
+		"囚旺休相死", // 寅 木
+		"囚旺休相死", // 卯 木
+		"相囚死休旺", // 辰 土
+		"死休囚旺相", // 巳 火
+		"死休囚旺相", // 午 火
+		"相囚死休旺", // 未 土
+		"旺死相囚休", // 申 金
+		"旺死相囚休", // 酉 金
+		"相囚死休旺", // 戌 土
+		"休相旺死囚", // 亥 水
+	}
+	for nZhi, strWant := range wantList {
+		for i, r := range []rune(strWant) {
+			if got := NewWangShuai(NewZhi(nZhi), NewWuXing(i)).String(); got != string(r) {
+				t.Errorf("%v月 %v = %s, want %s", NewZhi(nZhi), NewWuXing(i), got, string(r))
+			}
+		}
+	}
+}
+
+// TestSiLingTable 人元司令每个月一共三十天, 最后司令的是月支本气
+func TestSiLingTable(t *testing.T) {
+	for nZhi := 0; nZhi < 12; nZhi++ {
+		nTotal := 0
+		for i := 0; i < 3; i++ {
+			if silingganlist[nZhi][i] < 0 {
+				if silingdayslist[nZhi][i] != 0 {
+					t.Errorf("%v 第%d个司令没有天干却有天数", NewZhi(nZhi), i+1)
+				}
+				continue
+			}
+			nTotal += silingdayslist[nZhi][i]
+		}
+		if nTotal != 30 {
+			t.Errorf("%v 司令一共 %d 天, want 30", NewZhi(nZhi), nTotal)
+		}
+		// 最后一个司令的是本气
+		nLast := silingganlist[nZhi][1]
+		if silingganlist[nZhi][2] >= 0 {
+			nLast = silingganlist[nZhi][2]
+		}
+		if pBenQi := NewCangGan(0, NewZhi(nZhi)).Gan(0); pBenQi.Value() != nLast {
+			t.Errorf("%v 最后司令 %v, 本气 %v", NewZhi(nZhi), NewGan(nLast), pBenQi)
+		}
+	}
+}
+
+// TestSiLing 2024年立春是2月4日16点27分, 寅月 戊土七日 丙火七日 甲木十六日
+func TestSiLing(t *testing.T) {
+	cases := []struct {
+		nMonth, nDay int
+		nDays        int
+		strGan       string
+	}{
+		{2, 5, 1, "戊"},
+		{2, 11, 7, "戊"},
+		{2, 12, 8, "丙"},
+		{2, 19, 15, "甲"},
+		{3, 4, 29, "甲"},
+	}
+	for _, c := range cases {
+		pSiLing := GetBazi(2024, c.nMonth, c.nDay, 12, 0, 0, 1).SiZhu().SiLing()
+		if pSiLing.MonthZhi().String() != "寅" || pSiLing.Days() != c.nDays || pSiLing.Gan().String() != c.strGan {
+			t.Errorf("2024-%d-%d SiLing = %v月 第%d天 %v, want 寅月 第%d天 %s", c.nMonth, c.nDay, pSiLing.MonthZhi(), pSiLing.Days(), pSiLing.Gan(), c.nDays, c.strGan)
+		}
+	}
+}
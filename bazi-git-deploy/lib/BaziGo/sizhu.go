@@ -25,13 +25,19 @@ type TSiZhu struct {
 	// pHeHuaChong *THeHuaChong // 合化冲 这个暂时没写， 后面一定补。
 	pSolarDate *TSolarDate // 新历日期
 	pBaziDate  *TBaziDate  // 八字历日期
+	pSiLing    *TSiLing    // 人元司令
+	pTiaoHou   *TTiaoHou   // 调候用神
 	pXiYong    *TXiYong    // 喜用神
+	pGeJu      *TGeJu      // 格局
+
+	pShiShenTongJi *TShiShenTongJi // 十神统计
+	pGuanXiTu      *TGuanXiTu      // 干支关系图
 }
 
 func (m *TSiZhu) init() *TSiZhu {
 
-	// 通过公历 年月日计算日柱
-	nDayGan := m.pDayZhu.genDayGanZhi(m.pSolarDate.GetAllDays()).Gan().Value() // 获取日干(日主)
+	// 通过公历 年月日的儒略日数计算日柱
+	nDayGan := m.pDayZhu.genDayGanZhi(m.pSolarDate.JulianDayNumber()).Gan().Value() // 获取日干(日主)
 	// 通过小时 获取时柱
 	m.pHourZhu.setDayGan(nDayGan).genHourGanZhi(m.pSolarDate.Hour())
 	// 通过八字年来获取年柱
@@ -47,8 +53,23 @@ func (m *TSiZhu) init() *TSiZhu {
 	m.pDayZhu.genShenSha(dayGan, dayZhi)
 	m.pHourZhu.genShenSha(dayGan, dayZhi)
 	
+	// 人元司令, 喜用神按季节加权时要用
+	m.pSiLing = NewSiLing(m.pBaziDate, m.pSolarDate)
+
+	// 调候用神, 大运里有没有要等大运排好以后再查
+	m.pTiaoHou = NewTiaoHou(m, m.pBaziDate)
+
 	// 生成喜用神数据
 	m.pXiYong = NewXiYong(m)
+
+	// 格局要用到五行强度, 放在喜用神之后
+	m.pGeJu = NewGeJu(m)
+
+	// 十神统计
+	m.pShiShenTongJi = NewShiShenTongJi(m)
+
+	// 干支关系图, 命局的不会变, 算一次
+	m.pGuanXiTu = NewGuanXiTu(m, nil, nil)
 	return m
 }
 
@@ -106,3 +127,64 @@ func (m *TSiZhu) HourZhu() *TZhu {
 func (m *TSiZhu) XiYong() *TXiYong {
 	return m.pXiYong
 }
+
+// GeJu 格局
+func (m *TSiZhu) GeJu() *TGeJu {
+	return m.pGeJu
+}
+
+// TiaoHou 调候用神
+func (m *TSiZhu) TiaoHou() *TTiaoHou {
+	return m.pTiaoHou
+}
+
+// ShiShenTongJi 十神统计
+func (m *TSiZhu) ShiShenTongJi() *TShiShenTongJi {
+	return m.pShiShenTongJi
+}
+
+// SiLing 人元司令
+func (m *TSiZhu) SiLing() *TSiLing {
+	return m.pSiLing
+}
+
+// WangShuai 某个五行在月令的旺相休囚死
+func (m *TSiZhu) WangShuai(pWuXing *TWuXing) *TWangShuai {
+	return NewWangShuai(m.pMonthZhu.Zhi(), pWuXing)
+}
+
+// WangShuaiList 五行在月令的旺相休囚死, 金木水火土
+func (m *TSiZhu) WangShuaiList() [5]*TWangShuai {
+	var result [5]*TWangShuai
+	for i := range result {
+		result[i] = m.WangShuai(NewWuXing(i))
+	}
+	return result
+}
+
+// ShiShenWeightList 十神在命局里的强度, 下标是十神 0-9
+// 天干透出的算100(日干是日主自己, 不算), 地支藏干按本气 中气 余气的系数算
+func (m *TSiZhu) ShiShenWeightList() [10]int {
+	return m.ShiShenWeightListWith(cangganweightlist)
+}
+
+// ShiShenWeightListWith 十神在命局里的强度, 藏干按传入的系数表算
+func (m *TSiZhu) ShiShenWeightListWith(weightList TCangGanWeightList) [10]int {
+	var result [10]int
+	nDayGan := m.pDayZhu.Gan().Value()
+	for _, pZhu := range []*TZhu{m.pYearZhu, m.pMonthZhu, m.pDayZhu, m.pHourZhu} {
+		if pZhu != m.pDayZhu {
+			result[pZhu.ShiShen().Value()] += 100
+		}
+		pCangGan := NewCangGanWithWeightList(nDayGan, pZhu.Zhi(), weightList)
+		for i := 0; i < pCangGan.Size(); i++ {
+			result[pCangGan.ShiShen(i).Value()] += pCangGan.Weight(i)
+		}
+	}
+	return result
+}
+
+// ShiShenWeight 某个十神在命局里的强度, 比如正财有多旺
+func (m *TSiZhu) ShiShenWeight(pShiShen *TShiShen) int {
+	return m.ShiShenWeightList()[pShiShen.Value()]
+}
@@ -78,55 +78,22 @@ func (m *TSolarDate) Get64TimeStamp() int64 {
 
 // GetYearFrom64TimeStamp 从64位时间戳反推年
 func (m *TSolarDate) GetYearFrom64TimeStamp(nTimeStamp int64) *TSolarDate {
-	// 准备进行二分法
-	nLow := 0
-	nHigh := 3001
-
-	for {
-		nMid := (nLow + nHigh) / 2
-
-		// 拿到中间年的数据
-		v := NewSolarDate(nMid, 1, 1, 0, 0, 0).Get64TimeStamp()
-
-		if v <= nTimeStamp {
-			nLow = nMid
-		} else {
-			nHigh = nMid
-		}
-
-		if nHigh == nLow+1 {
-			break
-		}
-	}
-	m.nYear = nLow
+	m.nYear, _, _ = GetDateFromJulianDayNumber(getJulianDayNumberFrom64TimeStamp(nTimeStamp))
 	return m
 }
 
 // GetMonthFrom64TimeStamp 从64位时间戳反推月,
 func (m *TSolarDate) GetMonthFrom64TimeStamp(nTimeStamp int64) {
-	// 这里开始特殊处理
-	for i := 1; i <= 11; i++ {
-		if nTimeStamp < NewSolarDate(m.nYear, i+1, 1, 0, 0, 0).Get64TimeStamp() {
-			m.nMonth = i
-			return
-		}
-	}
-	m.nMonth = 12
+	_, m.nMonth, _ = GetDateFromJulianDayNumber(getJulianDayNumberFrom64TimeStamp(nTimeStamp))
 }
 
 // GetDayTimeFrom64TimeStamp 从64位时间戳反推其他参数
 func (m *TSolarDate) GetDayTimeFrom64TimeStamp(nTimeStamp int64) {
-	nTimeStamp -= NewSolarDate(m.nYear, m.nMonth, 1, 0, 0, 0).Get64TimeStamp()
+	_, _, m.nDay = GetDateFromJulianDayNumber(getJulianDayNumberFrom64TimeStamp(nTimeStamp))
 
-	// 计算日
-	m.nDay = int(nTimeStamp / (24 * 60 * 60))
-	// 扣掉日
-	nTimeStamp -= int64(m.nDay) * 24 * 60 * 60
+	// 当天零点以来的秒数
+	nTimeStamp -= floorDiv64(nTimeStamp, 24*60*60) * 24 * 60 * 60
 
-	m.nDay++ // 因为每个月的天数是从1开始的, 所以这里需要补1天
-	if m.nYear == 1582 && m.nMonth == 10 && m.nDay >= 5 {
-		m.nDay += 10 // 1582 年需要补10天
-	}
 	m.nHour = int(nTimeStamp / (60 * 60))
 	nTimeStamp -= int64(m.nHour) * 60 * 60
 	m.nMinute = int(nTimeStamp / 60)
@@ -134,6 +101,11 @@ func (m *TSolarDate) GetDayTimeFrom64TimeStamp(nTimeStamp int64) {
 	m.nSecond = int(nTimeStamp)
 }
 
+// getJulianDayNumberFrom64TimeStamp 64位时间戳所在日的儒略日数
+func getJulianDayNumberFrom64TimeStamp(nTimeStamp int64) int {
+	return int(floorDiv64(nTimeStamp, 24*60*60)) + jdnOfAllDaysOrigin
+}
+
 // GetMonthDays 取本月天数，不考虑 1582 年 10 月的特殊情况
 func (m *TSolarDate) GetMonthDays(nYear, nMonth int) int {
 	switch nMonth {
@@ -237,17 +209,15 @@ func (m *TSolarDate) GetTimeIsValid(nHour, nMinute, nSecond int) bool {
 }
 
 // GetAllDays 获得距公元原点的日数 这里是公历的年月日
+// 公元1年1月1日是第1日, 由儒略日数换算
 func (m *TSolarDate) GetAllDays() int {
-	nYear := m.Year()
-	nMonth := m.Month()
-	nDay := m.Day()
-	if m.GetDateIsValid(nYear, nMonth, nDay) {
-		return m.GetBasicDays(nYear, nMonth, nDay) + m.GetLeapDays(nYear, nMonth, nDay)
+	if m.GetDateIsValid(m.nYear, m.nMonth, m.nDay) {
+		return m.JulianDayNumber() - jdnOfAllDaysOrigin
 	}
 	return 0
 }
 
-// GetBasicDays 获取基本数据
+// GetBasicDays 获取基本数据, 与 GetLeapDays 相加是逐年逐月累加的日数算法, GetAllDays 已改用儒略日
 func (m *TSolarDate) GetBasicDays(nYear, nMonth, nDay int) int {
 	if !m.GetDateIsValid(nYear, nMonth, nDay) {
 		return 0
@@ -0,0 +1,179 @@
+package bazi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 岁运的特殊情况
+// 岁运并临  流年干支和大运干支相同
+// 伏吟      行运干支和命局某一柱相同
+// 反吟      行运和命局某一柱天干相冲, 地支相冲
+// 天克地冲  行运和命局某一柱天干相克(不是四冲的那几对), 地支相冲
+// 三刑      命局 大运 流年的地支凑成刑, 而且要有行运的地支参与
+
+// 岁运特殊情况的名字
+const (
+	SuiYunBingLin       = "岁运并临"
+	SuiYunFuYin         = "伏吟"
+	SuiYunFanYin        = "反吟"
+	SuiYunTianKeDiChong = "天克地冲"
+	SuiYunSanXing       = "三刑"
+)
+
+// 三刑的组合, 子卯和自刑两个字就成
+var sanxinglist = []struct {
+	zhiList []int
+	strName string
+}{
+	{[]int{2, 5, 8}, "无恩之刑"},  // 寅巳申
+	{[]int{1, 10, 7}, "恃势之刑"}, // 丑戌未
+	{[]int{0, 3}, "无礼之刑"},     // 子卯
+	{[]int{4, 4}, "自刑"},       // 辰辰
+	{[]int{6, 6}, "自刑"},       // 午午
+	{[]int{9, 9}, "自刑"},       // 酉酉
+	{[]int{11, 11}, "自刑"},     // 亥亥
+}
+
+// TSuiYunEvent 岁运的一个特殊情况
+type TSuiYunEvent struct {
+	strName   string // 岁运并临 伏吟 反吟 天克地冲 三刑
+	strSource string // 大运 流年
+	strDesc   string // 说明
+}
+
+// suiYunZhi 参与三刑判断的一个地支和它的来历
+type suiYunZhi struct {
+	pZhi    *TZhi
+	strFrom string // 年支 月支 日支 时支 大运 流年
+}
+
+// CalcSuiYun 行运的特殊情况, pDaYun 为空表示未起运
+// pLiuNian 为空时只看大运和命局; 不为空时只看流年带来的, 大运和命局之间的不再重复
+func CalcSuiYun(pSiZhu *TSiZhu, pDaYun *TGanZhi, pLiuNian *TGanZhi) []*TSuiYunEvent {
+	strSource, pGanZhi := "大运", pDaYun
+	if pLiuNian != nil {
+		strSource, pGanZhi = "流年", pLiuNian
+	}
+	if pGanZhi == nil {
+		return []*TSuiYunEvent{}
+	}
+
+	result := []*TSuiYunEvent{}
+	add := func(strName, strDesc string) {
+		result = append(result, &TSuiYunEvent{strName: strName, strSource: strSource, strDesc: strDesc})
+	}
+
+	// 岁运并临
+	if pLiuNian != nil && pDaYun != nil && pLiuNian.Value() == pDaYun.Value() {
+		add(SuiYunBingLin, fmt.Sprintf("流年%v与大运%v相同", pLiuNian, pDaYun))
+	}
+
+	// 伏吟 反吟 天克地冲
+	pGan, pZhi := pGanZhi.ExtractGanZhi()
+	zhuList := []*TZhu{pSiZhu.YearZhu(), pSiZhu.MonthZhu(), pSiZhu.DayZhu(), pSiZhu.HourZhu()}
+	for i, pZhu := range zhuList {
+		switch {
+		case pZhu.GanZhi().Value() == pGanZhi.Value():
+			add(SuiYunFuYin, fmt.Sprintf("%s%v伏吟%s", strSource, pGanZhi, zhunamelist[i]))
+		case pZhi.Clashes(pZhu.Zhi()) && pGan.Clashes(pZhu.Gan()):
+			add(SuiYunFanYin, fmt.Sprintf("%s%v反吟%s%v, 天冲地冲", strSource, pGanZhi, zhunamelist[i], pZhu.GanZhi()))
+		case pZhi.Clashes(pZhu.Zhi()) && (pGan.ToWuXing().Controls(pZhu.Gan().ToWuXing()) || pGan.ToWuXing().ControlledBy(pZhu.Gan().ToWuXing())):
+			add(SuiYunTianKeDiChong, fmt.Sprintf("%s%v与%s%v天克地冲", strSource, pGanZhi, zhunamelist[i], pZhu.GanZhi()))
+		}
+	}
+
+	// 三刑, 行运的地支放在最前面, 凑刑时优先用它
+	zhiList := []suiYunZhi{{pZhi, strSource}}
+	if pLiuNian != nil && pDaYun != nil {
+		_, pDaYunZhi := pDaYun.ExtractGanZhi()
+		zhiList = append(zhiList, suiYunZhi{pDaYunZhi, "大运"})
+	}
+	for i, pZhu := range zhuList {
+		zhiList = append(zhiList, suiYunZhi{pZhu.Zhi(), zhuzhilist[i]})
+	}
+	for _, sanxing := range sanxinglist {
+		if strDesc := matchSanXing(zhiList, sanxing.zhiList); strDesc != "" {
+			add(SuiYunSanXing, strDesc+sanxing.strName)
+		}
+	}
+	return result
+}
+
+// matchSanXing 地支里凑得出这个刑, 并且用到了第一个(行运的)地支, 返回 寅(流年)巳(年支)申(日支)
+func matchSanXing(zhiList []suiYunZhi, xingList []int) string {
+	isUsed := make([]bool, len(zhiList))
+	strDesc := ""
+	for _, nZhi := range xingList {
+		isFound := false
+		for i, p := range zhiList {
+			if !isUsed[i] && p.pZhi.Value() == nZhi {
+				isUsed[i] = true
+				isFound = true
+				strDesc += fmt.Sprintf("%v(%s)", p.pZhi, p.strFrom)
+				break
+			}
+		}
+		if !isFound {
+			return ""
+		}
+	}
+	if !isUsed[0] {
+		return ""
+	}
+	return strDesc
+}
+
+// DaYunSuiYun 第几步大运和命局之间的特殊情况
+func (m *TBazi) DaYunSuiYun(nIndex int) []*TSuiYunEvent {
+	return CalcSuiYun(m.pSiZhu, m.pDaYun.Zhu(nIndex).GanZhi(), nil)
+}
+
+// LiuNianSuiYun 某一年流年带来的特殊情况, 大运取这一年所在的那步
+func (m *TBazi) LiuNianSuiYun(nYear int) []*TSuiYunEvent {
+	var pDaYun *TGanZhi
+	if nIndex := m.DaYunIndexOfYear(nYear); nIndex >= 0 {
+		pDaYun = m.pDaYun.Zhu(nIndex).GanZhi()
+	}
+	return CalcSuiYun(m.pSiZhu, pDaYun, NewGanZhiFromYear(nYear))
+}
+
+// DaYunIndexOfYear 某一年所在的大运下标, 按起运那年换运, 未起运返回 -1, 超过12步算最后一步
+func (m *TBazi) DaYunIndexOfYear(nYear int) int {
+	nIndex := -1
+	for i := 0; i < m.pDaYun.Size(); i++ {
+		if m.Date().Year()+m.pDaYun.Age(i) <= nYear {
+			nIndex = i
+		}
+	}
+	return nIndex
+}
+
+// Name 岁运并临 伏吟 反吟 天克地冲 三刑
+func (m *TSuiYunEvent) Name() string {
+	return m.strName
+}
+
+// Source 大运 或者 流年
+func (m *TSuiYunEvent) Source() string {
+	return m.strSource
+}
+
+// Desc 说明
+func (m *TSuiYunEvent) Desc() string {
+	return m.strDesc
+}
+
+// String 打印用
+func (m *TSuiYunEvent) String() string {
+	return m.strName + ": " + m.strDesc
+}
+
+// MarshalJSON JSON 序列化
+func (m *TSuiYunEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name   string `json:"name"`
+		Source string `json:"source"`
+		Desc   string `json:"desc"`
+	}{m.strName, m.strSource, m.strDesc})
+}
@@ -0,0 +1,86 @@
+package bazi
+
+import (
+	"strings"
+	"testing"
+)
+
+// suiYunNames 特殊情况的名字, 空格隔开
+func suiYunNames(eventList []*TSuiYunEvent) string {
+	var strList []string
+	for _, pEvent := range eventList {
+		strList = append(strList, pEvent.Name())
+	}
+	return strings.Join(strList, " ")
+}
+
+// TestCalcSuiYun 庚午 辛巳 壬午 甲辰 这个命局遇到不同的流年
+func TestCalcSuiYun(t *testing.T) {
+	pSiZhu := GetBazi(1990, 5, 17, 8, 0, 0, 1).SiZhu()
+	pDaYun := NewGanZhi(18) // 壬午
+	cases := []struct {
+		nLiuNian int
+		strWant  string // 未起运
+		strDaYun string // 大运壬午
+	}{
+		{0, "反吟", "反吟"},             // 甲子 天冲地冲年柱庚午
+		{1, "", ""},                 // 乙丑
+		{6, "伏吟 三刑", "伏吟 三刑"},       // 庚午 伏吟年柱, 午午自刑
+		{12, "天克地冲 反吟", "天克地冲 反吟"},  // 丙子 克庚冲午, 冲壬冲午
+		{18, "伏吟 三刑", "岁运并临 伏吟 三刑"}, // 壬午
+		{23, "天克地冲", "天克地冲"},        // 丁亥 丁克辛, 亥冲巳
+		{34, "天克地冲", "天克地冲"},        // 戊戌 戊被甲克, 戌冲辰
+		{40, "伏吟 三刑", "伏吟 三刑"},      // 甲辰 伏吟时柱, 辰辰自刑
+		{46, "反吟", "反吟"},            // 庚戌 天冲地冲时柱甲辰
+		{20, "", ""},                // 甲申 命局没有寅, 凑不成寅巳申
+	}
+	for _, c := range cases {
+		pLiuNian := NewGanZhi(c.nLiuNian)
+		if got := suiYunNames(CalcSuiYun(pSiZhu, nil, pLiuNian)); got != c.strWant {
+			t.Errorf("流年%v = %q, want %q", pLiuNian, got, c.strWant)
+		}
+		if got := suiYunNames(CalcSuiYun(pSiZhu, pDaYun, pLiuNian)); got != c.strDaYun {
+			t.Errorf("大运%v 流年%v = %q, want %q", pDaYun, pLiuNian, got, c.strDaYun)
+		}
+	}
+
+	// 只看大运时, 大运壬午伏吟日柱, 和年支午自刑
+	if got := suiYunNames(CalcSuiYun(pSiZhu, pDaYun, nil)); got != "伏吟 三刑" {
+		t.Errorf("大运%v = %q", pDaYun, got)
+	}
+	if got := CalcSuiYun(pSiZhu, nil, nil); len(got) != 0 {
+		t.Errorf("没有大运流年 = %v, want empty", got)
+	}
+}
+
+// TestMatchSanXing 三刑要凑齐, 还要用到行运的地支
+func TestMatchSanXing(t *testing.T) {
+	zhiList := func(strZhi string) []suiYunZhi {
+		var result []suiYunZhi
+		for i, r := range []rune(strZhi) {
+			for nZhi := 0; nZhi < 12; nZhi++ {
+				if NewZhi(nZhi).String() == string(r) {
+					result = append(result, suiYunZhi{NewZhi(nZhi), []string{"流年", "年支", "月支", "日支", "时支"}[i]})
+				}
+			}
+		}
+		return result
+	}
+	cases := []struct {
+		strZhi  string // 第一个是流年
+		xing    []int
+		strWant string
+	}{
+		{"寅巳子申午", []int{2, 5, 8}, "寅(流年)巳(年支)申(日支)"},
+		{"子寅巳申午", []int{2, 5, 8}, ""}, // 命局自己凑齐, 流年没参与
+		{"寅巳子午午", []int{2, 5, 8}, ""}, // 缺申
+		{"卯子丑寅辰", []int{0, 3}, "子(年支)卯(流年)"},
+		{"午子丑午辰", []int{6, 6}, "午(流年)午(日支)"},
+		{"午子丑寅辰", []int{6, 6}, ""}, // 只有一个午
+	}
+	for _, c := range cases {
+		if got := matchSanXing(zhiList(c.strZhi), c.xing); got != c.strWant {
+			t.Errorf("%s %v = %q, want %q", c.strZhi, c.xing, got, c.strWant)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package bazi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 调候用神
+// 出自穷通宝鉴, 按日干和生月取用: 冬月寒要丙火暖局, 夏月燥要壬癸润局, 其余按月令的旺衰取用.
+// 表里每个格子只取最要紧的两个字, 第一个是调候用神, 第二个是辅佐, 只有一个字的辅佐为空.
+
+// 调候用神表, 行是日干, 列是八字月(寅月开始), 每格是 用神 和 辅佐的天干, -1 表示没有
+var tiaohoulist = [10][12][2]int{
+	//  寅       卯       辰       巳        午       未       申       酉       戌       亥       子        丑
+	{{2, 9}, {6, 2}, {6, 3}, {9, 3}, {9, 3}, {9, 3}, {6, 3}, {6, 3}, {6, 0}, {6, 3}, {3, 6}, {3, 6}},    // 甲
+	{{2, 9}, {2, 9}, {9, 2}, {9, -1}, {9, 2}, {9, 2}, {2, 9}, {9, 2}, {9, 7}, {2, 4}, {2, -1}, {2, -1}}, // 乙
+	{{8, 6}, {8, 5}, {8, 0}, {8, 9}, {8, 6}, {8, 6}, {8, 4}, {8, 9}, {0, 8}, {0, 4}, {8, 4}, {8, 0}},    // 丙
+	{{0, 6}, {6, 0}, {0, 6}, {0, 6}, {8, 6}, {0, 8}, {0, 6}, {0, 6}, {0, 6}, {0, 6}, {0, 6}, {0, 6}},    // 丁
+	{{2, 0}, {2, 0}, {0, 2}, {0, 2}, {8, 0}, {9, 2}, {2, 9}, {2, 9}, {0, 2}, {0, 2}, {2, 0}, {2, 0}},    // 戊
+	{{2, 6}, {0, 9}, {2, 9}, {9, 2}, {9, 2}, {9, 2}, {2, 9}, {2, 9}, {0, 2}, {2, 0}, {2, 0}, {2, 0}},    // 己
+	{{4, 0}, {3, 0}, {0, 3}, {8, 4}, {8, 9}, {3, 0}, {3, 0}, {3, 0}, {0, 8}, {3, 2}, {3, 0}, {2, 3}},    // 庚
+	{{5, 8}, {8, 0}, {8, 0}, {8, 0}, {8, 5}, {8, 6}, {8, 0}, {8, 0}, {8, 0}, {8, 2}, {2, 4}, {2, 8}},    // 辛
+	{{6, 2}, {4, 7}, {0, 6}, {8, 7}, {9, 6}, {7, 0}, {4, 3}, {0, 6}, {0, 2}, {4, 2}, {4, 2}, {2, 3}},    // 壬
+	{{7, 2}, {6, 7}, {2, 7}, {7, -1}, {6, 7}, {6, 7}, {3, -1}, {7, 2}, {7, 0}, {6, 7}, {2, 7}, {2, 3}}}  // 癸
+
+// NewTiaoHou 按日干和八字月取调候用神, 并查命局里有没有
+func NewTiaoHou(pSiZhu *TSiZhu, pBaziDate *TBaziDate) *TTiaoHou {
+	p := &TTiaoHou{}
+	p.init(pSiZhu, pBaziDate)
+	return p
+}
+
+// TTiaoHou 调候用神
+type TTiaoHou struct {
+	pDayGan    *TGan
+	nMonth     int          // 八字月, 1是寅月
+	pPrimary   *TTiaoHouGan // 调候用神
+	pSecondary *TTiaoHouGan // 辅佐, 可能为空
+}
+
+// TTiaoHouGan 调候用的一个天干和它在命局 大运里出现的位置
+type TTiaoHouGan struct {
+	pGan      *TGan
+	isTou     bool  // 命局天干透出
+	isCang    bool  // 命局地支藏
+	daYunList []int // 天干或藏干里有它的大运下标
+	pDaYun    *TDaYun
+}
+
+func (m *TTiaoHou) init(pSiZhu *TSiZhu, pBaziDate *TBaziDate) {
+	m.pDayGan = pSiZhu.DayZhu().Gan()
+	m.nMonth = pBaziDate.Month()
+
+	item := tiaohoulist[m.pDayGan.Value()][(m.nMonth+11)%12]
+	m.pPrimary = newTiaoHouGan(pSiZhu, item[0])
+	if item[1] >= 0 {
+		m.pSecondary = newTiaoHouGan(pSiZhu, item[1])
+	}
+}
+
+// newTiaoHouGan 查一个调候天干在命局里透不透, 藏不藏. 日干自己不算
+func newTiaoHouGan(pSiZhu *TSiZhu, nGan int) *TTiaoHouGan {
+	p := &TTiaoHouGan{pGan: NewGan(nGan)}
+	for _, pZhu := range []*TZhu{pSiZhu.YearZhu(), pSiZhu.MonthZhu(), pSiZhu.DayZhu(), pSiZhu.HourZhu()} {
+		if pZhu != pSiZhu.DayZhu() && pZhu.Gan().Value() == nGan {
+			p.isTou = true
+		}
+		if zhiHasCangGan(pZhu.Zhi(), nGan) {
+			p.isCang = true
+		}
+	}
+	return p
+}
+
+// zhiHasCangGan 地支藏干里有没有某个天干
+func zhiHasCangGan(pZhi *TZhi, nGan int) bool {
+	for _, n := range cangganlist[pZhi.Value()] {
+		if n == nGan {
+			return true
+		}
+	}
+	return false
+}
+
+// setDaYun 记下每个调候天干出现在哪几步大运
+func (m *TTiaoHou) setDaYun(pDaYun *TDaYun) {
+	for _, p := range m.GanList() {
+		p.pDaYun = pDaYun
+		p.daYunList = nil
+		for i := 0; i < pDaYun.Size(); i++ {
+			pZhu := pDaYun.Zhu(i)
+			if pZhu.Gan().Value() == p.pGan.Value() || zhiHasCangGan(pZhu.Zhi(), p.pGan.Value()) {
+				p.daYunList = append(p.daYunList, i)
+			}
+		}
+	}
+}
+
+// Primary 调候用神
+func (m *TTiaoHou) Primary() *TTiaoHouGan {
+	return m.pPrimary
+}
+
+// Secondary 辅佐, 没有时为空
+func (m *TTiaoHou) Secondary() *TTiaoHouGan {
+	return m.pSecondary
+}
+
+// GanList 用神和辅佐
+func (m *TTiaoHou) GanList() []*TTiaoHouGan {
+	if m.pSecondary == nil {
+		return []*TTiaoHouGan{m.pPrimary}
+	}
+	return []*TTiaoHouGan{m.pPrimary, m.pSecondary}
+}
+
+// IsSatisfied 命局里有调候用神(透或藏)
+func (m *TTiaoHou) IsSatisfied() bool {
+	return m.pPrimary.InNatal()
+}
+
+// String 打印用
+func (m *TTiaoHou) String() string {
+	var strList []string
+	for _, p := range m.GanList() {
+		strList = append(strList, p.String())
+	}
+	return fmt.Sprintf("调候: %v日生%v月, %s", m.pDayGan, NewZhi((m.nMonth+1)%12), strings.Join(strList, ", "))
+}
+
+// MarshalJSON JSON 序列化
+func (m *TTiaoHou) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Primary   *TTiaoHouGan `json:"primary"`
+		Secondary *TTiaoHouGan `json:"secondary,omitempty"`
+		Satisfied bool         `json:"satisfied"`
+	}{m.pPrimary, m.pSecondary, m.IsSatisfied()})
+}
+
+// Gan 调候的天干
+func (m *TTiaoHouGan) Gan() *TGan {
+	return m.pGan
+}
+
+// IsTou 命局天干透出
+func (m *TTiaoHouGan) IsTou() bool {
+	return m.isTou
+}
+
+// IsCang 命局地支藏干里有
+func (m *TTiaoHouGan) IsCang() bool {
+	return m.isCang
+}
+
+// InNatal 命局里有, 透或者藏
+func (m *TTiaoHouGan) InNatal() bool {
+	return m.isTou || m.isCang
+}
+
+// DaYunList 天干或藏干里有它的大运下标
+func (m *TTiaoHouGan) DaYunList() []int {
+	return m.daYunList
+}
+
+// String 打印用
+func (m *TTiaoHouGan) String() string {
+	strWhere := "命局不见"
+	switch {
+	case m.isTou:
+		strWhere = "透干"
+	case m.isCang:
+		strWhere = "藏支"
+	}
+	return m.pGan.String() + m.pGan.ToWuXing().String() + strWhere
+}
+
+// tiaoHouDaYun 出现调候天干的一步大运, JSON 用
+type tiaoHouDaYun struct {
+	Index  int    `json:"index"`
+	GanZhi string `json:"ganZhi"`
+	Age    int    `json:"age"` // 起运年龄
+}
+
+// MarshalJSON JSON 序列化
+func (m *TTiaoHouGan) MarshalJSON() ([]byte, error) {
+	daYunList := []tiaoHouDaYun{}
+	for _, i := range m.daYunList {
+		daYunList = append(daYunList, tiaoHouDaYun{i, m.pDaYun.Zhu(i).GanZhi().String(), m.pDaYun.Age(i)})
+	}
+	return json.Marshal(struct {
+		Gan    string         `json:"gan"`
+		WuXing string         `json:"wuXing"`
+		Tou    bool           `json:"tou"`
+		Cang   bool           `json:"cang"`
+		DaYun  []tiaoHouDaYun `json:"daYun"`
+	}{m.pGan.String(), m.pGan.ToWuXing().String(), m.isTou, m.isCang, daYunList})
+}
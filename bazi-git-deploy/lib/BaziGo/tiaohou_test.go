@@ -0,0 +1,62 @@
+package bazi
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestTiaoHouList 穷通宝鉴里几个常见的格子
+func TestTiaoHouList(t *testing.T) {
+	cases := []struct {
+		nDayGan int
+		nZhi    int
+		strWant string // 用神 辅佐
+	}{
+		{0, 2, "丙癸"},  // 甲木寅月, 丙癸并用
+		{0, 0, "丁庚"},  // 甲木子月, 丁先庚后
+		{1, 5, "癸"},   // 乙木巳月, 专用癸水
+		{2, 2, "壬庚"},  // 丙火寅月, 壬水为尊
+		{2, 10, "甲壬"}, // 丙火戌月, 忌土晦光, 先取甲
+		{8, 6, "癸庚"},  // 壬水午月, 取癸庚
+		{9, 5, "辛"},   // 癸水巳月, 专用辛金
+		{9, 8, "丁"},   // 癸水申月, 丁火为用
+	}
+	for _, c := range cases {
+		item := tiaohoulist[c.nDayGan][(c.nZhi+10)%12]
+		strGot := NewGan(item[0]).String()
+		if item[1] >= 0 {
+			strGot += NewGan(item[1]).String()
+		}
+		if strGot != c.strWant {
+			t.Errorf("%v日%v月 = %s, want %s", NewGan(c.nDayGan), NewZhi(c.nZhi), strGot, c.strWant)
+		}
+	}
+}
+
+// TestTiaoHou 命局里透不透 藏不藏, 以及出现在哪几步大运
+func TestTiaoHou(t *testing.T) {
+	cases := []struct {
+		nYear, nMonth, nDay, nHour, nSex int
+		strWant                          string
+		isSatisfied                      bool
+		strDaYun                         string // 用神和辅佐的大运下标
+	}{
+		// 庚午 辛巳 壬午 甲辰, 大运 壬午 癸未 甲申 乙酉 丙戌 丁亥 戊子 己丑 庚寅 辛卯 壬辰 癸巳
+		{1990, 5, 17, 8, 1, "调候: 壬日生巳月, 壬水命局不见, 辛金透干", false, "[0 2 5 10] [3 4 7 9]"},
+		// 癸卯 甲子 甲子 庚午, 午藏丁, 大运 癸亥 壬戌 辛酉 庚申 己未 戊午 丁巳 ...
+		{2024, 1, 1, 12, 1, "调候: 甲日生子月, 丁火藏支, 庚金透干", true, "[1 4 5 6] [3 6]"},
+		// 甲子 丁丑 己未 庚午 男命, 大运 戊寅 己卯 庚辰 辛巳 壬午 癸未 甲申 乙酉 丙戌 丁亥 ...
+		{1985, 1, 20, 12, 1, "调候: 己日生丑月, 丙火命局不见, 甲木透干", false, "[0 3 8] [0 6 9]"},
+		// 同一个命局女命逆排, 大运 丙子 乙亥 甲戌 癸酉 壬申 辛未 庚午 己巳 戊辰 丁卯 丙寅 乙丑
+		{1985, 1, 20, 12, 0, "调候: 己日生丑月, 丙火命局不见, 甲木透干", false, "[0 7 10] [1 2 10]"},
+	}
+	for _, c := range cases {
+		pTiaoHou := GetBazi(c.nYear, c.nMonth, c.nDay, c.nHour, 0, 0, c.nSex).SiZhu().TiaoHou()
+		if pTiaoHou.String() != c.strWant || pTiaoHou.IsSatisfied() != c.isSatisfied {
+			t.Errorf("%d-%d-%d TiaoHou = %v %v, want %s %v", c.nYear, c.nMonth, c.nDay, pTiaoHou, pTiaoHou.IsSatisfied(), c.strWant, c.isSatisfied)
+		}
+		if got := fmt.Sprint(pTiaoHou.Primary().DaYunList(), pTiaoHou.Secondary().DaYunList()); got != c.strDaYun {
+			t.Errorf("%d-%d-%d 性别%d DaYunList = %s, want %s", c.nYear, c.nMonth, c.nDay, c.nSex, got, c.strDaYun)
+		}
+	}
+}
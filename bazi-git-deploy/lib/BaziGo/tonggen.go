@@ -0,0 +1,257 @@
+package bazi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 通根
+// 天干在地支藏干里见到同五行的字就是通根, 有根的天干才有力. 根的轻重看藏干的位置, 本气根最重, 中气次之, 余气最轻,
+// 系数和藏干表一样. 天干下面自己那个地支里就有根叫坐支(坐根), 比远处的根更有力.
+// 地支藏干里有生天干的字(印)不算根, 只算生扶, 单独统计.
+// 命局的天干看命局四个地支和当前大运的地支, 大运 流年的天干也看这几个地支, 另外再看自己坐的地支.
+
+// TTongGen 一个天干的通根
+type TTongGen struct {
+	pGan     *TGan
+	strFrom  string          // 年干 月干 日干 时干 大运 流年
+	rootList []*TTongGenRoot // 根, 按地支的顺序
+	nSheng   int             // 地支藏干里生它的字的系数合计
+}
+
+// TTongGenRoot 天干的一个根
+type TTongGenRoot struct {
+	pZhi     *TZhi
+	strFrom  string // 年支 月支 日支 时支 大运 流年
+	pCangGan *TGan  // 同五行的藏干
+	nRole    int    // 本气 中气 余气
+	nWeight  int    // 系数
+	isZuo    bool   // 是不是坐支
+}
+
+// tongGenZhi 看根的一个地支
+type tongGenZhi struct {
+	pZhi    *TZhi
+	strFrom string
+	isZuo   bool // 天干坐在这个地支上
+}
+
+// newTongGen 在这些地支里找天干的根
+func newTongGen(pGan *TGan, strFrom string, zhiList []tongGenZhi) *TTongGen {
+	p := &TTongGen{pGan: pGan, strFrom: strFrom, rootList: []*TTongGenRoot{}}
+	pWuXing := pGan.ToWuXing()
+	for _, item := range zhiList {
+		pCangGan := NewCangGan(pGan.Value(), item.pZhi)
+		for i := 0; i < pCangGan.Size(); i++ {
+			pCangWuXing := pCangGan.Gan(i).ToWuXing()
+			switch {
+			case pCangWuXing.Value() == pWuXing.Value():
+				p.rootList = append(p.rootList, &TTongGenRoot{
+					pZhi:     item.pZhi,
+					strFrom:  item.strFrom,
+					pCangGan: pCangGan.Gan(i),
+					nRole:    pCangGan.Role(i),
+					nWeight:  pCangGan.Weight(i),
+					isZuo:    item.isZuo,
+				})
+			case pCangWuXing.Generates(pWuXing):
+				p.nSheng += pCangGan.Weight(i)
+			}
+		}
+	}
+	return p
+}
+
+// natalTongGenZhi 命局四个地支, 再加上大运的地支, pDaYun 为空表示未起运
+func natalTongGenZhi(pSiZhu *TSiZhu, pDaYun *TGanZhi) []tongGenZhi {
+	var result []tongGenZhi
+	for i, pZhu := range []*TZhu{pSiZhu.YearZhu(), pSiZhu.MonthZhu(), pSiZhu.DayZhu(), pSiZhu.HourZhu()} {
+		result = append(result, tongGenZhi{pZhu.Zhi(), zhuzhilist[i], false})
+	}
+	if pDaYun != nil {
+		_, pZhi := pDaYun.ExtractGanZhi()
+		result = append(result, tongGenZhi{pZhi, "大运", false})
+	}
+	return result
+}
+
+// CalcTongGen 命局四个天干的通根, 有大运时加上大运的地支
+func CalcTongGen(pSiZhu *TSiZhu, pDaYun *TGanZhi) [4]*TTongGen {
+	var result [4]*TTongGen
+	for i, pZhu := range []*TZhu{pSiZhu.YearZhu(), pSiZhu.MonthZhu(), pSiZhu.DayZhu(), pSiZhu.HourZhu()} {
+		zhiList := natalTongGenZhi(pSiZhu, pDaYun)
+		zhiList[i].isZuo = true
+		result[i] = newTongGen(pZhu.Gan(), zhuganlist[i], zhiList)
+	}
+	return result
+}
+
+// CalcXingYunTongGen 行运天干的通根, 看命局四个地支, 大运的地支和自己坐的地支
+// pLiuNian 为空时看大运的天干, 不为空时看流年的天干. pDaYun 为空表示未起运, 这时 pLiuNian 不能为空
+func CalcXingYunTongGen(pSiZhu *TSiZhu, pDaYun *TGanZhi, pLiuNian *TGanZhi) *TTongGen {
+	zhiList := natalTongGenZhi(pSiZhu, pDaYun)
+	if pLiuNian == nil {
+		pGan, _ := pDaYun.ExtractGanZhi()
+		zhiList[len(zhiList)-1].isZuo = true
+		return newTongGen(pGan, "大运", zhiList)
+	}
+	pGan, pZhi := pLiuNian.ExtractGanZhi()
+	zhiList = append(zhiList, tongGenZhi{pZhi, "流年", true})
+	return newTongGen(pGan, "流年", zhiList)
+}
+
+// TongGenList 命局四个天干在命局地支里的通根
+func (m *TSiZhu) TongGenList() [4]*TTongGen {
+	return CalcTongGen(m, nil)
+}
+
+// TongGenList 某一年命局 大运 流年天干的通根, 地支看命局和这一年所在的大运, 未起运时没有大运那一项
+func (m *TBazi) TongGenList(nYear int) []*TTongGen {
+	var pDaYun *TGanZhi
+	if nIndex := m.DaYunIndexOfYear(nYear); nIndex >= 0 {
+		pDaYun = m.pDaYun.Zhu(nIndex).GanZhi()
+	}
+
+	natalList := CalcTongGen(m.pSiZhu, pDaYun)
+	result := natalList[:]
+	if pDaYun != nil {
+		result = append(result, CalcXingYunTongGen(m.pSiZhu, pDaYun, nil))
+	}
+	return append(result, CalcXingYunTongGen(m.pSiZhu, pDaYun, NewGanZhiFromYear(nYear)))
+}
+
+// Gan 天干
+func (m *TTongGen) Gan() *TGan {
+	return m.pGan
+}
+
+// From 年干 月干 日干 时干 大运 流年
+func (m *TTongGen) From() string {
+	return m.strFrom
+}
+
+// RootList 根
+func (m *TTongGen) RootList() []*TTongGenRoot {
+	return m.rootList
+}
+
+// IsRooted 有没有根
+func (m *TTongGen) IsRooted() bool {
+	return len(m.rootList) > 0
+}
+
+// IsZuoZhi 坐支, 自己坐的地支里有根
+func (m *TTongGen) IsZuoZhi() bool {
+	for _, pRoot := range m.rootList {
+		if pRoot.isZuo {
+			return true
+		}
+	}
+	return false
+}
+
+// Strength 根的系数合计
+func (m *TTongGen) Strength() int {
+	nStrength := 0
+	for _, pRoot := range m.rootList {
+		nStrength += pRoot.nWeight
+	}
+	return nStrength
+}
+
+// Sheng 地支藏干里生它的字的系数合计
+func (m *TTongGen) Sheng() int {
+	return m.nSheng
+}
+
+// Level 最重的根 通根本气 通根中气 通根余气 无根
+func (m *TTongGen) Level() string {
+	nRole := -1
+	for _, pRoot := range m.rootList {
+		if nRole < 0 || pRoot.nRole < nRole {
+			nRole = pRoot.nRole
+		}
+	}
+	if nRole < 0 {
+		return "无根"
+	}
+	return "通根" + GetCangGanRoleFromNumber(nRole)
+}
+
+// String 打印用
+func (m *TTongGen) String() string {
+	var strList []string
+	for _, pRoot := range m.rootList {
+		strList = append(strList, pRoot.String())
+	}
+	strResult := fmt.Sprintf("%s%v %s", m.strFrom, m.pGan, m.Level())
+	if m.IsZuoZhi() {
+		strResult += " 坐支"
+	}
+	if len(strList) > 0 {
+		strResult += ": " + strings.Join(strList, " ")
+	}
+	return strResult
+}
+
+// MarshalJSON JSON 序列化
+func (m *TTongGen) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Gan      string          `json:"gan"`
+		From     string          `json:"from"`
+		Level    string          `json:"level"`
+		Rooted   bool            `json:"rooted"`
+		ZuoZhi   bool            `json:"zuoZhi"`
+		Strength int             `json:"strength"`
+		Sheng    int             `json:"sheng"`
+		Roots    []*TTongGenRoot `json:"roots"`
+	}{m.pGan.String(), m.strFrom, m.Level(), m.IsRooted(), m.IsZuoZhi(), m.Strength(), m.nSheng, m.rootList})
+}
+
+// Zhi 根所在的地支
+func (m *TTongGenRoot) Zhi() *TZhi {
+	return m.pZhi
+}
+
+// From 年支 月支 日支 时支 大运 流年
+func (m *TTongGenRoot) From() string {
+	return m.strFrom
+}
+
+// CangGan 同五行的藏干
+func (m *TTongGenRoot) CangGan() *TGan {
+	return m.pCangGan
+}
+
+// Role 本气 中气 余气
+func (m *TTongGenRoot) Role() int {
+	return m.nRole
+}
+
+// Weight 系数
+func (m *TTongGenRoot) Weight() int {
+	return m.nWeight
+}
+
+// IsZuo 是不是坐支
+func (m *TTongGenRoot) IsZuo() bool {
+	return m.isZuo
+}
+
+// String 打印用, 比如 日支寅(甲本气)
+func (m *TTongGenRoot) String() string {
+	return fmt.Sprintf("%s%v(%v%s)", m.strFrom, m.pZhi, m.pCangGan, GetCangGanRoleFromNumber(m.nRole))
+}
+
+// MarshalJSON JSON 序列化
+func (m *TTongGenRoot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Zhi     string `json:"zhi"`
+		From    string `json:"from"`
+		CangGan string `json:"cangGan"`
+		Role    string `json:"role"`
+		Weight  int    `json:"weight"`
+		Zuo     bool   `json:"zuo"`
+	}{m.pZhi.String(), m.strFrom, m.pCangGan.String(), GetCangGanRoleFromNumber(m.nRole), m.nWeight, m.isZuo})
+}
@@ -0,0 +1,63 @@
+package bazi
+
+import "testing"
+
+// TestTongGenSiZhu 命局四个天干在命局地支里的根
+func TestTongGenSiZhu(t *testing.T) {
+	cases := []struct {
+		nYear, nMonth, nDay, nHour int
+		wantList                   [4]string
+		strengthList               [4]int
+		shengList                  [4]int
+	}{
+		// 庚午 辛巳 壬午 甲辰, 土生金 午己30两个 巳戊10 辰戊60
+		{1990, 5, 17, 8, [4]string{
+			"年干庚 通根中气: 月支巳(庚中气)",
+			"月干辛 通根中气 坐支: 月支巳(庚中气)",
+			"日干壬 通根中气: 时支辰(癸中气)",
+			"时干甲 通根余气 坐支: 时支辰(乙余气)",
+		}, [4]int{30, 30, 30, 10}, [4]int{130, 130, 30, 30}},
+		// 癸卯 甲子 甲子 庚午, 子水生甲, 庚金无根, 只有午里的己土生它
+		{2024, 1, 1, 12, [4]string{
+			"年干癸 通根本气: 月支子(癸本气) 日支子(癸本气)",
+			"月干甲 通根本气: 年支卯(乙本气)",
+			"日干甲 通根本气: 年支卯(乙本气)",
+			"时干庚 无根",
+		}, [4]int{200, 100, 100, 0}, [4]int{0, 200, 200, 30}},
+	}
+	for _, c := range cases {
+		for i, pTongGen := range GetBazi(c.nYear, c.nMonth, c.nDay, c.nHour, 0, 0, 1).SiZhu().TongGenList() {
+			if pTongGen.String() != c.wantList[i] || pTongGen.Strength() != c.strengthList[i] || pTongGen.Sheng() != c.shengList[i] {
+				t.Errorf("%d-%d-%d TongGenList[%d] = %s %d %d, want %s %d %d", c.nYear, c.nMonth, c.nDay, i,
+					pTongGen, pTongGen.Strength(), pTongGen.Sheng(), c.wantList[i], c.strengthList[i], c.shengList[i])
+			}
+			if pTongGen.IsRooted() != (pTongGen.Level() != "无根") {
+				t.Errorf("%d-%d-%d TongGenList[%d] IsRooted = %v", c.nYear, c.nMonth, c.nDay, i, pTongGen.IsRooted())
+			}
+		}
+	}
+}
+
+// TestTongGenXingYun 庚午 辛巳 壬午 甲辰 男命 2024年, 大运甲申 流年甲辰
+func TestTongGenXingYun(t *testing.T) {
+	wantList := []struct {
+		strWant   string
+		nStrength int
+	}{
+		{"年干庚 通根本气: 月支巳(庚中气) 大运申(庚本气)", 90},
+		{"月干辛 通根本气 坐支: 月支巳(庚中气) 大运申(庚本气)", 90},
+		{"日干壬 通根中气: 时支辰(癸中气) 大运申(壬中气)", 60},
+		{"时干甲 通根余气 坐支: 时支辰(乙余气)", 10},
+		{"大运甲 通根余气: 时支辰(乙余气)", 10},
+		{"流年甲 通根余气 坐支: 时支辰(乙余气) 流年辰(乙余气)", 20},
+	}
+	tongGenList := GetBazi(1990, 5, 17, 8, 0, 0, 1).TongGenList(2024)
+	if len(tongGenList) != len(wantList) {
+		t.Fatalf("TongGenList(2024) = %v", tongGenList)
+	}
+	for i, pTongGen := range tongGenList {
+		if pTongGen.String() != wantList[i].strWant || pTongGen.Strength() != wantList[i].nStrength {
+			t.Errorf("TongGenList[%d] = %s %d, want %s %d", i, pTongGen, pTongGen.Strength(), wantList[i].strWant, wantList[i].nStrength)
+		}
+	}
+}
@@ -46,6 +46,21 @@ func GetWuXingFromGan(pGan *TGan) *TWuXing {
 	return pGan.ToWuXing()
 }
 
+// 五行相生 金生水 木生火 水生木 火生土 土生金
+var wuxingshenglist = [5]int{2, 3, 1, 4, 0}
+
+// 五行相克 金克木 木克土 水克火 火克金 土克水
+var wuxingkelist = [5]int{1, 4, 3, 0, 2}
+
+// 五行关系, 都是以自己为主
+const (
+	WuXingSheng    = "生"  // 我生
+	WuXingKe       = "克"  // 我克
+	WuXingBeiSheng = "被生" // 生我
+	WuXingBeiKe    = "被克" // 克我
+	WuXingTong     = "同"  // 比和
+)
+
 // NewWuXing 创建五行
 func NewWuXing(nValue int) *TWuXing {
 	nValue %= 5
@@ -80,3 +95,53 @@ func (m *TWuXing) String() string {
 func (m *TWuXing) Color() string {
 	return GetWuXingColorFromNumber(m.Value())
 }
+
+// Generates 我生, 比如 木生火
+func (m *TWuXing) Generates(other *TWuXing) bool {
+	return wuxingshenglist[m.Value()] == other.Value()
+}
+
+// Controls 我克, 比如 木克土
+func (m *TWuXing) Controls(other *TWuXing) bool {
+	return wuxingkelist[m.Value()] == other.Value()
+}
+
+// GeneratedBy 生我, 比如 木被水生
+func (m *TWuXing) GeneratedBy(other *TWuXing) bool {
+	return other.Generates(m)
+}
+
+// ControlledBy 克我, 比如 木被金克
+func (m *TWuXing) ControlledBy(other *TWuXing) bool {
+	return other.Controls(m)
+}
+
+// Relation 我和另一个五行的关系, 生 克 被生 被克 同
+func (m *TWuXing) Relation(other *TWuXing) string {
+	switch {
+	case m.Value() == other.Value():
+		return WuXingTong
+	case m.Generates(other):
+		return WuXingSheng
+	case m.Controls(other):
+		return WuXingKe
+	case m.GeneratedBy(other):
+		return WuXingBeiSheng
+	}
+	return WuXingBeiKe
+}
+
+// Child 我生的五行, 比如 木的子是火
+func (m *TWuXing) Child() *TWuXing {
+	return NewWuXing(wuxingshenglist[m.Value()])
+}
+
+// Mother 生我的五行, 比如 木的母是水
+func (m *TWuXing) Mother() *TWuXing {
+	for i, nChild := range wuxingshenglist {
+		if nChild == m.Value() {
+			return NewWuXing(i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package bazi
+
+import "testing"
+
+// TestWuXingRelation 五行两两的生克, 木生火 火生土 土生金 金生水 水生木, 木克土 土克水 水克火 火克金 金克木
+func TestWuXingRelation(t *testing.T) {
+	shengList := orderedPairSet("木火 火土 土金 金水 水木")
+	keList := orderedPairSet("木土 土水 水火 火金 金木")
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			p1, p2 := NewWuXing(i), NewWuXing(j)
+			strPair := p1.String() + p2.String()
+			strBack := p2.String() + p1.String()
+			isSheng, isBeiSheng := shengList[strPair], shengList[strBack]
+			isKe, isBeiKe := keList[strPair], keList[strBack]
+
+			if got := p1.Generates(p2); got != isSheng {
+				t.Errorf("%s Generates = %v", strPair, got)
+			}
+			if got := p1.GeneratedBy(p2); got != isBeiSheng {
+				t.Errorf("%s GeneratedBy = %v", strPair, got)
+			}
+			if got := p1.Controls(p2); got != isKe {
+				t.Errorf("%s Controls = %v", strPair, got)
+			}
+			if got := p1.ControlledBy(p2); got != isBeiKe {
+				t.Errorf("%s ControlledBy = %v", strPair, got)
+			}
+
+			strWant := WuXingTong
+			switch {
+			case isSheng:
+				strWant = WuXingSheng
+			case isKe:
+				strWant = WuXingKe
+			case isBeiSheng:
+				strWant = WuXingBeiSheng
+			case isBeiKe:
+				strWant = WuXingBeiKe
+			}
+			if got := p1.Relation(p2); got != strWant {
+				t.Errorf("%s Relation = %s, want %s", strPair, got, strWant)
+			}
+		}
+	}
+}
+
+// TestWuXingChildMother 子和母
+func TestWuXingChildMother(t *testing.T) {
+	cases := []struct {
+		strSelf, strChild, strMother string
+	}{
+		{"金", "水", "土"},
+		{"木", "火", "水"},
+		{"水", "木", "金"},
+		{"火", "土", "木"},
+		{"土", "金", "火"},
+	}
+	for i, c := range cases {
+		pWuXing := NewWuXing(i)
+		if pWuXing.String() != c.strSelf {
+			t.Fatalf("NewWuXing(%d) = %v, want %s", i, pWuXing, c.strSelf)
+		}
+		if got := pWuXing.Child().String(); got != c.strChild {
+			t.Errorf("%s Child = %s, want %s", c.strSelf, got, c.strChild)
+		}
+		if got := pWuXing.Mother().String(); got != c.strMother {
+			t.Errorf("%s Mother = %s, want %s", c.strSelf, got, c.strMother)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package bazi
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // 喜用神
 // 喜用神是中国传统八字命理学上的术语， 喜用神是喜神与用神的合称。
@@ -20,10 +23,25 @@ func NewXiYong(pSiZhu *TSiZhu) *TXiYong {
 	return p
 }
 
+// NewXiYongWithGanZhi 命局加上行运干支(大运 流年等)之后的五行强度
+// 行运的干支和命局一样按月令换算强度
+func NewXiYongWithGanZhi(pSiZhu *TSiZhu, pGanZhiList ...*TGanZhi) *TXiYong {
+	p := &TXiYong{}
+	p.init(pSiZhu)
+	nMonthZhi := pSiZhu.MonthZhu().Zhi().Value()
+	for _, pGanZhi := range pGanZhiList {
+		pGan, pZhi := pGanZhi.ExtractGanZhi()
+		p.addGan(nMonthZhi, pGan)
+		p.addZhi(nMonthZhi, pZhi)
+	}
+	return p
+}
+
 // TXiYong 喜用神
 type TXiYong struct {
 	pSiZhu     *TSiZhu
 	wuxingList [5]int // 金木水火土
+	isWeighted bool   // 是否已经按季节加权
 
 }
 
@@ -39,36 +57,71 @@ func (m *TXiYong) init(pSiZhu *TSiZhu) {
 	// log.Println("月支是", nMonthZhi, pSiZhu.MonthZhu.Zhi.Str)
 
 	// 3. 根据四柱天干, 换算强度
-	m.wuxingList[pSiZhu.YearZhu().Gan().ToWuXing().Value()] += tianganqiangdulist[nMonthZhi][pSiZhu.YearZhu().Gan().Value()]
-	m.wuxingList[pSiZhu.MonthZhu().Gan().ToWuXing().Value()] += tianganqiangdulist[nMonthZhi][pSiZhu.MonthZhu().Gan().Value()]
-	m.wuxingList[pSiZhu.DayZhu().Gan().ToWuXing().Value()] += tianganqiangdulist[nMonthZhi][pSiZhu.DayZhu().Gan().Value()]
-	m.wuxingList[pSiZhu.HourZhu().Gan().ToWuXing().Value()] += tianganqiangdulist[nMonthZhi][pSiZhu.HourZhu().Gan().Value()]
+	m.addGan(nMonthZhi, pSiZhu.YearZhu().Gan())
+	m.addGan(nMonthZhi, pSiZhu.MonthZhu().Gan())
+	m.addGan(nMonthZhi, pSiZhu.DayZhu().Gan())
+	m.addGan(nMonthZhi, pSiZhu.HourZhu().Gan())
 
 	// 4. 根据四柱地支, 换算强度
+	m.addZhi(nMonthZhi, pSiZhu.YearZhu().Zhi())
+	m.addZhi(nMonthZhi, pSiZhu.MonthZhu().Zhi())
+	m.addZhi(nMonthZhi, pSiZhu.DayZhu().Zhi())
+	m.addZhi(nMonthZhi, pSiZhu.HourZhu().Zhi())
+}
 
-	pYearCangGan := pSiZhu.YearZhu().CangGan()
-	for i := 0; i < pYearCangGan.Size(); i++ {
-		nCangGan := pYearCangGan.Gan(i).Value()
-		m.wuxingList[pYearCangGan.Gan(i).ToWuXing().Value()] += dizhiqiangdulist[nMonthZhi][nCangGan]
-	}
+// addGan 加上一个天干的强度
+func (m *TXiYong) addGan(nMonthZhi int, pGan *TGan) {
+	m.wuxingList[pGan.ToWuXing().Value()] += tianganqiangdulist[nMonthZhi][pGan.Value()]
+}
 
-	pMonthCangGan := pSiZhu.MonthZhu().CangGan()
-	for i := 0; i < pMonthCangGan.Size(); i++ {
-		nCangGan := pMonthCangGan.Gan(i).Value()
-		m.wuxingList[pMonthCangGan.Gan(i).ToWuXing().Value()] += dizhiqiangdulist[nMonthZhi][nCangGan]
+// addZhi 加上一个地支的强度, 按藏干查表
+// 地支强度表的行从寅月开始, 每个地支占三列, 依次是藏干表里的三个藏干
+func (m *TXiYong) addZhi(nMonthZhi int, pZhi *TZhi) {
+	nRow := (nMonthZhi + 10) % 12
+	nZhi := pZhi.Value()
+	for i := 0; i < 3; i++ {
+		nCangGan := cangganlist[nZhi][i]
+		if nCangGan < 0 {
+			break
+		}
+		m.wuxingList[NewGan(nCangGan).ToWuXing().Value()] += dizhiqiangdulist[nRow][nZhi*3+i]
 	}
+}
 
-	pDayCangGan := pSiZhu.DayZhu().CangGan()
-	for i := 0; i < pDayCangGan.Size(); i++ {
-		nCangGan := pDayCangGan.Gan(i).Value()
-		m.wuxingList[pDayCangGan.Gan(i).ToWuXing().Value()] += dizhiqiangdulist[nMonthZhi][nCangGan]
+// Weighted 按旺相休囚死和人元司令加权后的五行强度, 原来的不变
+// 强度表已经按月令换算过, 这里再按季节拉开差距, 司令的天干五行再加一点
+func (m *TXiYong) Weighted() *TXiYong {
+	p := *m
+	if p.isWeighted {
+		return &p
 	}
-
-	pHourCangGan := pSiZhu.HourZhu().CangGan()
-	for i := 0; i < pHourCangGan.Size(); i++ {
-		nCangGan := pHourCangGan.Gan(i).Value()
-		m.wuxingList[pHourCangGan.Gan(i).ToWuXing().Value()] += dizhiqiangdulist[nMonthZhi][nCangGan]
+	p.isWeighted = true
+	for i := range p.wuxingList {
+		p.wuxingList[i] = p.wuxingList[i] * wangshuaiweightlist[m.pSiZhu.WangShuai(NewWuXing(i)).Value()] / 100
 	}
+	nSiLing := m.pSiZhu.SiLing().Gan().ToWuXing().Value()
+	p.wuxingList[nSiLing] = p.wuxingList[nSiLing] * siLingWeight / 100
+	return &p
+}
+
+// TiaoHou 调候用神, 强度看的是平衡, 调候看的是寒暖燥湿, 取用时两个都要看
+func (m *TXiYong) TiaoHou() *TTiaoHou {
+	return m.pSiZhu.TiaoHou()
+}
+
+// IsWeighted 是否按季节加权过
+func (m *TXiYong) IsWeighted() bool {
+	return m.isWeighted
+}
+
+// WuXingList 五行强度列表, 金木水火土
+func (m *TXiYong) WuXingList() [5]int {
+	return m.wuxingList
+}
+
+// Strength 某个五行的强度
+func (m *TXiYong) Strength(pWuXing *TWuXing) int {
+	return m.wuxingList[pWuXing.Value()]
 }
 
 func (m *TXiYong) String() string {
@@ -79,10 +132,20 @@ func (m *TXiYong) String() string {
 	strResult += fmt.Sprintf("水强度 = %d\n", m.wuxingList[2])
 	strResult += fmt.Sprintf("火强度 = %d\n", m.wuxingList[3])
 	strResult += fmt.Sprintf("土强度 = %d\n", m.wuxingList[4])
+	strResult += m.TiaoHou().String() + "\n"
 
 	return strResult
 }
 
+// MarshalJSON JSON 序列化
+func (m *TXiYong) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		WuXing   [5]int    `json:"wuXing"` // 金木水火土
+		Weighted bool      `json:"weighted"`
+		TiaoHou  *TTiaoHou `json:"tiaoHou"`
+	}{m.wuxingList, m.isWeighted, m.TiaoHou()})
+}
+
 // 天干地支强度测试
 
 // 天干强度表
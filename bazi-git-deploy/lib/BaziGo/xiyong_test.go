@@ -0,0 +1,40 @@
+package bazi
+
+import "testing"
+
+// TestXiYongWuXingList 按强度表手算的五行强度, 金木水火土
+// 天干查天干强度表的月令那一行; 地支查地支强度表, 行从寅月开始, 每个地支三列对应三个藏干
+func TestXiYongWuXingList(t *testing.T) {
+	cases := []struct {
+		nYear, nMonth, nDay, nHour int
+		strSiZhu                   string
+		wantList                   [5]int
+	}{
+		// 巳月: 庚1060 辛1060 壬1060 甲1000; 午 丁1140 己0 两个, 巳 丙840 戊0 庚300, 辰 戊600 乙300 癸200
+		{1990, 5, 17, 8, "庚午辛巳壬午甲辰", [5]int{2420, 1300, 1260, 3120, 600}},
+		// 子月: 癸1200 甲1200 甲1200 庚1000; 卯 乙1200, 子 癸1200 两个, 午 丁1000 己0
+		{2024, 1, 1, 12, "癸卯甲子甲子庚午", [5]int{1000, 3600, 3600, 1000, 0}},
+		// 丑月: 甲1060 丁1000 己1100 庚1140; 子 癸1100, 丑 己550 癸330 辛228, 未 己550 乙212 丁300, 午 丁1000 己0
+		{1985, 1, 20, 12, "甲子丁丑己未庚午", [5]int{1368, 1272, 1430, 2300, 2200}},
+	}
+	for _, c := range cases {
+		pSiZhu := GetBazi(c.nYear, c.nMonth, c.nDay, c.nHour, 0, 0, 1).SiZhu()
+		strSiZhu := pSiZhu.YearZhu().GanZhi().String() + pSiZhu.MonthZhu().GanZhi().String() + pSiZhu.DayZhu().GanZhi().String() + pSiZhu.HourZhu().GanZhi().String()
+		if strSiZhu != c.strSiZhu {
+			t.Fatalf("%d-%d-%d 四柱 = %s, want %s", c.nYear, c.nMonth, c.nDay, strSiZhu, c.strSiZhu)
+		}
+		if got := pSiZhu.XiYong().WuXingList(); got != c.wantList {
+			t.Errorf("%s WuXingList = %v, want %v", strSiZhu, got, c.wantList)
+		}
+	}
+}
+
+// TestXiYongWithGanZhi 行运的干支按命局的月令换算
+func TestXiYongWithGanZhi(t *testing.T) {
+	pSiZhu := GetBazi(2024, 1, 1, 12, 0, 0, 1).SiZhu()
+	// 子月 丙1000, 午 丁1000 己0
+	pXiYong := NewXiYongWithGanZhi(pSiZhu, NewGanZhi(42)) // 丙午
+	if got, want := pXiYong.WuXingList(), [5]int{1000, 3600, 3600, 3000, 0}; got != want {
+		t.Errorf("加丙午 WuXingList = %v, want %v", got, want)
+	}
+}
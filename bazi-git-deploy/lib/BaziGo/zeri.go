@@ -0,0 +1,341 @@
+package bazi
+
+import (
+	"fmt"
+	"sort"
+)
+
+// 择日
+// 在一段日期里为某件事挑日子, 综合以下几项打分:
+// 1. 建除十二神, 以月建(节气月的月支)起建, 日支顺数: 建 除 满 平 定 执 破 危 成 收 开 闭
+// 2. 黄道黑道, 十二值神以月支定青龙起点, 青龙 明堂 金匮 天德 玉堂 司命为黄道, 其余为黑道
+//    口诀: 寅申需加子, 卯酉却居寅, 辰戌龙位上, 巳亥午中寻, 子午临申位, 丑未戌上行
+// 3. 天德 月德, 以月支查日干
+// 4. 与命主日支 年支的冲合, 以及以命主日柱查出的神煞
+// 时辰同样按黄道黑道挑选, 以日支定青龙起点.
+
+// GetJianChuFromNumber 从数字获得建除十二神名, 0-11
+func GetJianChuFromNumber(nValue int) string {
+	switch nValue {
+	case 0:
+		return "建"
+	case 1:
+		return "除"
+	case 2:
+		return "满"
+	case 3:
+		return "平"
+	case 4:
+		return "定"
+	case 5:
+		return "执"
+	case 6:
+		return "破"
+	case 7:
+		return "危"
+	case 8:
+		return "成"
+	case 9:
+		return "收"
+	case 10:
+		return "开"
+	case 11:
+		return "闭"
+	}
+	return ""
+}
+
+// GetZhiShenFromNumber 从数字获得十二值神名, 0-11
+func GetZhiShenFromNumber(nValue int) string {
+	switch nValue {
+	case 0:
+		return "青龙"
+	case 1:
+		return "明堂"
+	case 2:
+		return "天刑"
+	case 3:
+		return "朱雀"
+	case 4:
+		return "金匮"
+	case 5:
+		return "天德"
+	case 6:
+		return "白虎"
+	case 7:
+		return "玉堂"
+	case 8:
+		return "天牢"
+	case 9:
+		return "玄武"
+	case 10:
+		return "司命"
+	case 11:
+		return "勾陈"
+	}
+	return ""
+}
+
+// getJianChu 建除, 月支起建, 日支顺数
+func getJianChu(nMonthZhi int, nDayZhi int) int {
+	return (nDayZhi - nMonthZhi + 12) % 12
+}
+
+// getZhiShen 十二值神, nBaseZhi 是月支(排日)或日支(排时)
+func getZhiShen(nBaseZhi int, nZhi int) int {
+	// 寅申起子, 卯酉起寅, 每过一支青龙进两位
+	nStart := ((nBaseZhi-2)*2%12 + 12) % 12
+	return (nZhi - nStart + 12) % 12
+}
+
+// isHuangDao 值神是否黄道
+func isHuangDao(nZhiShen int) bool {
+	switch nZhiShen {
+	case 0, 1, 4, 5, 7, 10:
+		return true
+	}
+	return false
+}
+
+// TZeRiProfile 择日事项, 不同的事项宜忌不同
+type TZeRiProfile struct {
+	Key         string   // 事项代码, 比如 wedding
+	Name        string   // 事项名称, 比如 嫁娶
+	GoodJianChu []int    // 宜的建除
+	BadJianChu  []int    // 忌的建除
+	GoodShenSha []string // 喜见的神煞
+	BadShenSha  []string // 忌见的神煞
+}
+
+// 内置的择日事项
+var zeriprofilelist = map[string]*TZeRiProfile{
+	"wedding": {
+		Key:         "wedding",
+		Name:        "嫁娶",
+		GoodJianChu: []int{4, 8, 10},    // 定 成 开
+		BadJianChu:  []int{0, 6, 7, 11}, // 建 破 危 闭
+		GoodShenSha: []string{"天乙贵人", "天德贵人", "月德贵人", "桃花"},
+		BadShenSha:  []string{"孤辰", "寡宿", "劫煞", "亡神", "羊刃"},
+	},
+	"moving": {
+		Key:         "moving",
+		Name:        "移徙",
+		GoodJianChu: []int{2, 8, 9, 10}, // 满 成 收 开
+		BadJianChu:  []int{6, 7, 11},    // 破 危 闭
+		GoodShenSha: []string{"天乙贵人", "天德贵人", "月德贵人", "驿马"},
+		BadShenSha:  []string{"劫煞", "亡神", "天罗地网"},
+	},
+	"opening": {
+		Key:         "opening",
+		Name:        "开市",
+		GoodJianChu: []int{2, 4, 8, 9, 10}, // 满 定 成 收 开
+		BadJianChu:  []int{3, 6, 11},       // 平 破 闭
+		GoodShenSha: []string{"天乙贵人", "禄神", "将星", "文昌贵人"},
+		BadShenSha:  []string{"劫煞", "亡神", "羊刃"},
+	},
+}
+
+// RegisterZeRiProfile 注册择日事项, 同名覆盖
+func RegisterZeRiProfile(pProfile *TZeRiProfile) {
+	zeriprofilelist[pProfile.Key] = pProfile
+}
+
+// GetZeRiProfile 获取择日事项, 没有的话返回 nil
+func GetZeRiProfile(strKey string) *TZeRiProfile {
+	return zeriprofilelist[strKey]
+}
+
+// GetZeRiProfileKeys 所有择日事项的代码
+func GetZeRiProfileKeys() []string {
+	var result []string
+	for strKey := range zeriprofilelist {
+		result = append(result, strKey)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// TZeRiDay 择日的候选日
+type TZeRiDay struct {
+	Date     *TSolarDate `json:"date"`     // 日期
+	GanZhi   string      `json:"ganZhi"`   // 日干支
+	JianChu  string      `json:"jianChu"`  // 建除
+	ZhiShen  string      `json:"zhiShen"`  // 值神
+	HuangDao bool        `json:"huangDao"` // 是否黄道日
+	Score    int         `json:"score"`    // 评分
+	Reasons  []string    `json:"reasons"`  // 评分依据
+	Hours    []string    `json:"hours"`    // 可用的黄道时辰
+}
+
+// NewZeRi 新建择日, 在 pStart 到 pEnd(含)之间为 pProfile 事项挑日子, baziList 是相关的命主
+func NewZeRi(pProfile *TZeRiProfile, pStart *TSolarDate, pEnd *TSolarDate, baziList ...*TBazi) *TZeRi {
+	p := &TZeRi{
+		pProfile: pProfile,
+		baziList: baziList,
+	}
+	p.init(pStart, pEnd)
+	return p
+}
+
+// TZeRi 择日
+type TZeRi struct {
+	pProfile *TZeRiProfile
+	baziList []*TBazi
+	dayList  []*TZeRiDay // 按评分从高到低
+}
+
+func (m *TZeRi) init(pStart *TSolarDate, pEnd *TSolarDate) *TZeRi {
+	for nJDN := pStart.JulianDayNumber(); nJDN <= pEnd.JulianDayNumber(); nJDN++ {
+		if pDay := m.evalDay(newSolarDateFromJulianDayNumber(nJDN)); pDay != nil {
+			m.dayList = append(m.dayList, pDay)
+		}
+	}
+	// 评分高的在前, 同分的早的在前
+	sort.SliceStable(m.dayList, func(i, j int) bool {
+		return m.dayList[i].Score > m.dayList[j].Score
+	})
+	return m
+}
+
+// evalDay 给一天打分, 超出节气表范围的日子返回 nil
+func (m *TZeRi) evalDay(pDate *TSolarDate) *TZeRiDay {
+	// 日期取正午, 避开子时换日和节气交接的零点
+	pNoon := NewSolarDate(pDate.Year(), pDate.Month(), pDate.Day(), 12, 0, 0)
+	pPreviousJie, _ := GetJieQiDate(pNoon)
+	if pPreviousJie == nil {
+		return nil
+	}
+
+	nMonthZhi := (pPreviousJie.JieQi.Month() + 1) % 12
+	pGanZhi := NewGanZhiFromJulianDay(pDate.JulianDayNumber())
+	pDayGan, pDayZhi := pGanZhi.ExtractGanZhi()
+	nDayGan := pDayGan.Value()
+	nDayZhi := pDayZhi.Value()
+
+	nJianChu := getJianChu(nMonthZhi, nDayZhi)
+	nZhiShen := getZhiShen(nMonthZhi, nDayZhi)
+
+	pDay := &TZeRiDay{
+		Date:     pDate,
+		GanZhi:   pGanZhi.String(),
+		JianChu:  GetJianChuFromNumber(nJianChu),
+		ZhiShen:  GetZhiShenFromNumber(nZhiShen),
+		HuangDao: isHuangDao(nZhiShen),
+		Score:    50,
+	}
+	add := func(nScore int, strReason string) {
+		pDay.Score += nScore
+		pDay.Reasons = append(pDay.Reasons, fmt.Sprintf("%+d %s", nScore, strReason))
+	}
+
+	// 1. 黄道黑道
+	if pDay.HuangDao {
+		add(10, pDay.ZhiShen+"黄道")
+	} else {
+		add(-10, pDay.ZhiShen+"黑道")
+	}
+
+	// 2. 建除
+	if containsInt(m.pProfile.GoodJianChu, nJianChu) {
+		add(12, pDay.JianChu+"日宜"+m.pProfile.Name)
+	}
+	if containsInt(m.pProfile.BadJianChu, nJianChu) {
+		add(-15, pDay.JianChu+"日忌"+m.pProfile.Name)
+	}
+
+	// 3. 天德 月德
+	if checkTianDeGuiRen(nMonthZhi, nDayGan) {
+		add(6, "天德")
+	}
+	if checkYueDeGuiRen(nMonthZhi, nDayGan) {
+		add(6, "月德")
+	}
+
+	// 4. 命主
+	for i, pBazi := range m.baziList {
+		strWho := fmt.Sprintf("命主%d", i+1)
+		pNatalDayZhi := pBazi.SiZhu().DayZhu().Zhi()
+		pNatalYearZhi := pBazi.SiZhu().YearZhu().Zhi()
+
+		if pDayZhi.Clashes(pNatalDayZhi) {
+			add(-20, fmt.Sprintf("日支%s冲%s日支%s", pDayZhi, strWho, pNatalDayZhi))
+		}
+		if pDayZhi.Clashes(pNatalYearZhi) {
+			add(-15, fmt.Sprintf("日支%s冲%s年支%s", pDayZhi, strWho, pNatalYearZhi))
+		}
+		if pDayZhi.CombinesWith(pNatalDayZhi) {
+			add(5, fmt.Sprintf("日支%s合%s日支%s", pDayZhi, strWho, pNatalDayZhi))
+		} else if pDayZhi.TrinesWith(pNatalDayZhi) {
+			add(3, fmt.Sprintf("日支%s与%s日支%s三合", pDayZhi, strWho, pNatalDayZhi))
+		}
+
+		pShenSha := CalcShenSha(pBazi.SiZhu().DayZhu().Gan(), pBazi.SiZhu().DayZhu().Zhi(), pDayGan, pDayZhi, "择日")
+		for _, strShenSha := range pShenSha.GetList() {
+			if containsString(m.pProfile.GoodShenSha, strShenSha) {
+				add(4, strWho+strShenSha)
+			}
+			if containsString(m.pProfile.BadShenSha, strShenSha) {
+				add(-5, strWho+strShenSha)
+			}
+		}
+	}
+
+	// 5. 黄道时辰, 不冲命主日支年支
+	for nZhi := 0; nZhi < 12; nZhi++ {
+		if !isHuangDao(getZhiShen(nDayZhi, nZhi)) || m.isClashNatal(nZhi) {
+			continue
+		}
+		// 五鼠遁, 甲己还加甲
+		nGan := (nDayGan%5*2 + nZhi) % 10
+		pDay.Hours = append(pDay.Hours, NewGan(nGan).String()+NewZhi(nZhi).String()+"时")
+	}
+	return pDay
+}
+
+// isClashNatal 是否冲任一命主的日支或年支
+func (m *TZeRi) isClashNatal(nZhi int) bool {
+	for _, pBazi := range m.baziList {
+		if pZhi := NewZhi(nZhi); pZhi.Clashes(pBazi.SiZhu().DayZhu().Zhi()) || pZhi.Clashes(pBazi.SiZhu().YearZhu().Zhi()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Profile 择日事项
+func (m *TZeRi) Profile() *TZeRiProfile {
+	return m.pProfile
+}
+
+// Days 所有候选日, 按评分从高到低
+func (m *TZeRi) Days() []*TZeRiDay {
+	return m.dayList
+}
+
+// Top 评分最高的前N天
+func (m *TZeRi) Top(nCount int) []*TZeRiDay {
+	if nCount < 0 || nCount > len(m.dayList) {
+		nCount = len(m.dayList)
+	}
+	return m.dayList[:nCount]
+}
+
+// containsInt 列表里是否有某个数
+func containsInt(list []int, nValue int) bool {
+	for _, v := range list {
+		if v == nValue {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString 列表里是否有某个字符串
+func containsString(list []string, strValue string) bool {
+	for _, v := range list {
+		if v == strValue {
+			return true
+		}
+	}
+	return false
+}
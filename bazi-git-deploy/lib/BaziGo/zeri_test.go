@@ -0,0 +1,104 @@
+package bazi
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestJianChu 月建那天是建, 冲月建的是破, 寅月从寅日起建
+func TestJianChu(t *testing.T) {
+	for nMonthZhi := 0; nMonthZhi < 12; nMonthZhi++ {
+		if got := GetJianChuFromNumber(getJianChu(nMonthZhi, nMonthZhi)); got != "建" {
+			t.Errorf("%v月%v日 = %s, want 建", NewZhi(nMonthZhi), NewZhi(nMonthZhi), got)
+		}
+		if got := GetJianChuFromNumber(getJianChu(nMonthZhi, (nMonthZhi+6)%12)); got != "破" {
+			t.Errorf("%v月%v日 = %s, want 破", NewZhi(nMonthZhi), NewZhi((nMonthZhi+6)%12), got)
+		}
+	}
+	// 寅月 子日到亥日
+	want := "开闭建除满平定执破危成收"
+	for nDayZhi := 0; nDayZhi < 12; nDayZhi++ {
+		if got := GetJianChuFromNumber(getJianChu(2, nDayZhi)); got != string([]rune(want)[nDayZhi]) {
+			t.Errorf("寅月%v日 = %s, want %s", NewZhi(nDayZhi), got, string([]rune(want)[nDayZhi]))
+		}
+	}
+}
+
+// TestZhiShen 寅申需加子, 卯酉却居寅, 辰戌龙位上, 巳亥午中寻, 子午临申位, 丑未戌上行
+func TestZhiShen(t *testing.T) {
+	// 子丑寅卯辰巳午未申酉戌亥 各自的青龙在哪一支
+	qingLong := "申戌子寅辰午申戌子寅辰午"
+	for nBase := 0; nBase < 12; nBase++ {
+		strHuangDao := ""
+		for nZhi := 0; nZhi < 12; nZhi++ {
+			nZhiShen := getZhiShen(nBase, nZhi)
+			if nZhiShen == 0 && NewZhi(nZhi).String() != string([]rune(qingLong)[nBase]) {
+				t.Errorf("%v 青龙在%v, want %s", NewZhi(nBase), NewZhi(nZhi), string([]rune(qingLong)[nBase]))
+			}
+			if isHuangDao(nZhiShen) {
+				strHuangDao += NewZhi(nZhi).String()
+			}
+		}
+		if nBase == 0 && strHuangDao != "子丑卯午申酉" {
+			t.Errorf("子 黄道 = %s, want 子丑卯午申酉", strHuangDao)
+		}
+	}
+	// 青龙起依次是 青龙 明堂 天刑 朱雀 金匮 天德 白虎 玉堂 天牢 玄武 司命 勾陈
+	want := "青龙明堂天刑朱雀金匮天德白虎玉堂天牢玄武司命勾陈"
+	for nZhi := 0; nZhi < 12; nZhi++ {
+		// 寅月青龙在子
+		if got := GetZhiShenFromNumber(getZhiShen(2, nZhi)); got != string([]rune(want)[nZhi*2:nZhi*2+2]) {
+			t.Errorf("寅月%v日 = %s, want %s", NewZhi(nZhi), got, string([]rune(want)[nZhi*2:nZhi*2+2]))
+		}
+	}
+}
+
+// TestZeRi 2024年1月1日到8日挑嫁娶的日子, 1月6日小寒之后是丑月
+func TestZeRi(t *testing.T) {
+	pZeRi := NewZeRi(GetZeRiProfile("wedding"), NewSolarDate(2024, 1, 1, 0, 0, 0), NewSolarDate(2024, 1, 8, 0, 0, 0))
+	cases := []struct {
+		nDay     int
+		strDay   string // 干支 建除 值神
+		nScore   int
+		strHours string
+	}{
+		{6, "己巳定玉堂", 72, "乙丑时 戊辰时 庚午时 辛未时 甲戌时 乙亥时"},
+		{2, "乙丑除天德", 60, "戊寅时 己卯时 辛巳时 甲申时 丙戌时 丁亥时"},
+		{4, "丁卯平玉堂", 60, "庚子时 壬寅时 癸卯时 丙午时 丁未时 己酉时"},
+		{5, "戊辰定天牢", 52, "甲寅时 丙辰时 丁巳时 庚申时 辛酉时 癸亥时"},
+		{1, "甲子建金匮", 51, "甲子时 乙丑时 丁卯时 庚午时 壬申时 癸酉时"}, // 五鼠遁 甲己还加甲
+		{7, "庚午执天牢", 46, "丙子时 丁丑时 己卯时 壬午时 甲申时 乙酉时"}, // 乙庚丙作初
+		{3, "丙寅满白虎", 40, "戊子时 己丑时 壬辰时 癸巳时 乙未时 戊戌时"}, // 丙辛从戊起
+		{8, "辛未破玄武", 25, "庚寅时 辛卯时 癸巳时 丙申时 戊戌时 己亥时"},
+	}
+	dayList := pZeRi.Days()
+	if len(dayList) != len(cases) {
+		t.Fatalf("Days = %d, want %d", len(dayList), len(cases))
+	}
+	for i, c := range cases {
+		pDay := dayList[i]
+		if pDay.Date.Day() != c.nDay || pDay.GanZhi+pDay.JianChu+pDay.ZhiShen != c.strDay || pDay.Score != c.nScore {
+			t.Errorf("Days[%d] = %d日 %s%s%s %d, want %d日 %s %d", i, pDay.Date.Day(), pDay.GanZhi, pDay.JianChu, pDay.ZhiShen, pDay.Score, c.nDay, c.strDay, c.nScore)
+		}
+		if got := strings.Join(pDay.Hours, " "); got != c.strHours {
+			t.Errorf("%d日 Hours = %s, want %s", c.nDay, got, c.strHours)
+		}
+	}
+	if got := pZeRi.Top(2); len(got) != 2 || got[0] != dayList[0] {
+		t.Errorf("Top(2) = %v", got)
+	}
+}
+
+// TestZeRiNatal 命主 庚午 辛巳 壬午 甲辰, 子日冲日支年支, 子时也不能用
+func TestZeRiNatal(t *testing.T) {
+	pDate := NewSolarDate(2024, 1, 1, 0, 0, 0)
+	pZeRi := NewZeRi(GetZeRiProfile("wedding"), pDate, pDate, GetBazi(1990, 5, 17, 8, 0, 0, 1))
+	pDay := pZeRi.Days()[0]
+	wantReasons := "+10 金匮黄道, -15 建日忌嫁娶, +6 天德, -20 日支子冲命主1日支午, -15 日支子冲命主1年支午, -5 命主1羊刃"
+	if got := strings.Join(pDay.Reasons, ", "); got != wantReasons || pDay.Score != 11 {
+		t.Errorf("Reasons = %s %d, want %s 11", got, pDay.Score, wantReasons)
+	}
+	if got := strings.Join(pDay.Hours, " "); got != "乙丑时 丁卯时 庚午时 壬申时 癸酉时" {
+		t.Errorf("Hours = %s", got)
+	}
+}
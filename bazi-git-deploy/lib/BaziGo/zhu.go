@@ -128,11 +128,11 @@ func (m *TZhu) genMonthGanZhi(nMonth int, nYearGan int) *TZhu {
 }
 
 // genDayGanZhi 生成日干支
-func (m *TZhu) genDayGanZhi(nAllDays int) *TZhu {
+func (m *TZhu) genDayGanZhi(nJulianDayNumber int) *TZhu {
 
-	// 通过总天数来获取
-	// 获得八字年的干支，0-59 对应 甲子到癸亥
-	m.pGanZhi = NewGanZhiFromDay(nAllDays)
+	// 通过儒略日数来获取
+	// 获得八字日的干支，0-59 对应 甲子到癸亥
+	m.pGanZhi = NewGanZhiFromJulianDay(nJulianDayNumber)
 	// 拆分干支
 	// 获得八字年的干0-9 对应 甲到癸
 	// 获得八字年的支0-11 对应 子到亥
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"math"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
+
+	"bazi/fortune"
 
 	bazi "github.com/warrially/BaziGo"
 )
@@ -22,27 +24,33 @@ type BaziRequest struct {
 	Sex    int `json:"sex"`
 }
 
+// MatchRequest 合婚请求
+type MatchRequest struct {
+	Person1 BaziRequest `json:"person1"`
+	Person2 BaziRequest `json:"person2"`
+}
+
+// ZeRiRequest 择日请求
+type ZeRiRequest struct {
+	Event  string        `json:"event"`  // 事项 wedding moving opening
+	Start  string        `json:"start"`  // 开始日期 2024-01-01
+	End    string        `json:"end"`    // 结束日期 2024-03-31
+	Charts []BaziRequest `json:"charts"` // 相关命主, 可以为空
+	Limit  int           `json:"limit"`  // 返回前N天, 默认10
+}
+
 type BaziResponse struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
 }
 
-// FortuneKLineData K线数据结构
-type FortuneKLineData struct {
-	Year  int     `json:"year"`  // 年份
-	Open  float64 `json:"open"`  // 开盘价(年初运势)
-	Close float64 `json:"close"` // 收盘价(年末运势)
-	High  float64 `json:"high"`  // 最高(该年最佳运势)
-	Low   float64 `json:"low"`   // 最低(该年最差运势)
-	Score float64 `json:"score"` // 综合评分
-}
-
 // FortuneResponse 运势响应
 type FortuneResponse struct {
-	Success bool               `json:"success"`
-	Data    []FortuneKLineData `json:"data,omitempty"`
-	Error   string             `json:"error,omitempty"`
+	Success bool            `json:"success"`
+	Model   string          `json:"model,omitempty"` // 使用的模型
+	Data    []fortune.KLine `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
 }
 
 func main() {
@@ -63,6 +71,9 @@ func main() {
 		port = ":" + port
 	}
 
+	// 注册运势模型
+	registerFortuneModels()
+
 	// 静态文件服务
 	http.Handle("/", http.FileServer(http.Dir("./public")))
 
@@ -70,6 +81,9 @@ func main() {
 	http.HandleFunc("/api/bazi", handleBazi)
 	http.HandleFunc("/api/bazi/html", handleBaziHTML)
 	http.HandleFunc("/api/bazi/fortune", handleFortune)
+	http.HandleFunc("/api/bazi/wuxing", handleWuXing)
+	http.HandleFunc("/api/bazi/match", handleMatch)
+	http.HandleFunc("/api/zeri", handleZeRi)
 
 	log.Printf("八字服务器启动在 http://localhost%s", port)
 	log.Fatal(http.ListenAndServe(port, nil))
@@ -137,11 +151,41 @@ func handleBazi(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 构建响应数据
+	// 构建响应数据, 六亲受冲合从今年开始看
+	thisYear := time.Now().Year()
 	data := map[string]interface{}{
 		"solarDate": pBazi.Date().String(),
 		"lunarDate": pBazi.LunarDate().String(),
 		"siZhu":     pBazi.SiZhu().String(),
+		"geJu":      pBazi.SiZhu().GeJu(),
+		"wangShuai": pBazi.SiZhu().WangShuaiList(),
+		"siLing":    pBazi.SiZhu().SiLing(),
+		"xiYong":    pBazi.SiZhu().XiYong(),
+		"liuQin": map[string]interface{}{
+			"items":   pBazi.LiuQin(),
+			"daYun":   pBazi.LiuQin().DaYunEvents(thisYear),
+			"liuNian": pBazi.LiuQin().LiuNianEvents(thisYear, 10),
+		},
+		"suiYun":        suiYunData(pBazi, thisYear, 10),
+		"naYin":         naYinData(pBazi, thisYear, 10),
+		"cangGan":       cangGanData(pBazi),
+		"shiShen":       shiShenData(pBazi),
+		"shiShenTongJi": pBazi.SiZhu().ShiShenTongJi(),
+		"heHua": map[string]interface{}{
+			"siZhu":   pBazi.SiZhu().HeHuaList(),
+			"year":    thisYear,
+			"xingYun": pBazi.HeHuaList(thisYear),
+		},
+		"guanXiTu": map[string]interface{}{
+			"siZhu":   pBazi.SiZhu().GuanXiTu(),
+			"year":    thisYear,
+			"xingYun": pBazi.GuanXiTu(thisYear),
+		},
+		"tongGen": map[string]interface{}{
+			"siZhu":   pBazi.SiZhu().TongGenList(),
+			"year":    thisYear,
+			"xingYun": pBazi.TongGenList(thisYear),
+		},
 		"daYun":     pBazi.DaYun().String(),
 		"qiYunDate": pBazi.QiYunDate().String(),
 	}
@@ -153,6 +197,89 @@ func handleBazi(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// suiYunData 每步大运和命局的特殊情况, 以及从 nStartYear 起 nYears 年里有特殊情况的流年
+func suiYunData(pBazi *bazi.TBazi, nStartYear, nYears int) map[string]interface{} {
+	daYunList := []map[string]interface{}{}
+	for i := 0; i < pBazi.DaYun().Size(); i++ {
+		daYunList = append(daYunList, map[string]interface{}{
+			"index":  i,
+			"ganZhi": pBazi.DaYun().Zhu(i).GanZhi().String(),
+			"year":   pBazi.Date().Year() + pBazi.DaYun().Age(i),
+			"events": pBazi.DaYunSuiYun(i),
+		})
+	}
+	liuNianList := []map[string]interface{}{}
+	for nYear := nStartYear; nYear < nStartYear+nYears; nYear++ {
+		if events := pBazi.LiuNianSuiYun(nYear); len(events) > 0 {
+			liuNianList = append(liuNianList, map[string]interface{}{
+				"year":   nYear,
+				"ganZhi": bazi.NewGanZhiFromYear(nYear).String(),
+				"events": events,
+			})
+		}
+	}
+	return map[string]interface{}{"daYun": daYunList, "liuNian": liuNianList}
+}
+
+// naYinData 四柱纳音, 年柱和日柱纳音的生克, 每步大运和从 nStartYear 起 nYears 年流年的纳音生克
+func naYinData(pBazi *bazi.TBazi, nStartYear, nYears int) map[string]interface{} {
+	daYunList := []map[string]interface{}{}
+	for i := 0; i < pBazi.DaYun().Size(); i++ {
+		daYunList = append(daYunList, map[string]interface{}{
+			"index":  i,
+			"ganZhi": pBazi.DaYun().Zhu(i).GanZhi().String(),
+			"naYin":  pBazi.DaYun().Zhu(i).GanZhi().ToNaYin(),
+			"guanXi": pBazi.DaYunNaYin(i),
+		})
+	}
+	liuNianList := []map[string]interface{}{}
+	for nYear := nStartYear; nYear < nStartYear+nYears; nYear++ {
+		liuNianList = append(liuNianList, map[string]interface{}{
+			"year":   nYear,
+			"ganZhi": bazi.NewGanZhiFromYear(nYear).String(),
+			"naYin":  bazi.NewGanZhiFromYear(nYear).ToNaYin(),
+			"guanXi": pBazi.LiuNianNaYin(nYear),
+		})
+	}
+	return map[string]interface{}{
+		"siZhu":   pBazi.SiZhu().NaYinList(),
+		"guanXi":  pBazi.SiZhu().NaYinGuanXi(),
+		"daYun":   daYunList,
+		"liuNian": liuNianList,
+	}
+}
+
+// cangGanData 四柱地支的藏干, 十神, 本气中气余气和系数
+func cangGanData(pBazi *bazi.TBazi) [][]map[string]interface{} {
+	result := [][]map[string]interface{}{}
+	for _, pZhu := range []*bazi.TZhu{pBazi.SiZhu().YearZhu(), pBazi.SiZhu().MonthZhu(), pBazi.SiZhu().DayZhu(), pBazi.SiZhu().HourZhu()} {
+		pCangGan := pZhu.CangGan()
+		list := []map[string]interface{}{}
+		for i := 0; i < pCangGan.Size(); i++ {
+			list = append(list, map[string]interface{}{
+				"gan":     pCangGan.Gan(i).String(),
+				"shiShen": bazi.GetShiShenLongFromNumber(pCangGan.ShiShen(i).Value()),
+				"role":    pCangGan.RoleName(i),
+				"weight":  pCangGan.Weight(i),
+			})
+		}
+		result = append(result, list)
+	}
+	return result
+}
+
+// shiShenData 十神在命局里的强度, 天干100, 藏干按系数
+func shiShenData(pBazi *bazi.TBazi) []map[string]interface{} {
+	result := []map[string]interface{}{}
+	for i, nWeight := range pBazi.SiZhu().ShiShenWeightList() {
+		result = append(result, map[string]interface{}{
+			"shiShen": bazi.GetShiShenLongFromNumber(i),
+			"weight":  nWeight,
+		})
+	}
+	return result
+}
+
 // handleBaziHTML 返回 HTML 格式的八字信息
 func handleBaziHTML(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -205,6 +332,17 @@ func handleFortune(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 选择模型, ?model=xxx, 不填用默认模型
+	model, ok := fortune.Get(r.URL.Query().Get("model"))
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(FortuneResponse{
+			Success: false,
+			Error:   fmt.Sprintf("未知的运势模型, 可选: %v", fortune.Names()),
+		})
+		return
+	}
+
 	// 验证输入
 	if req.Year < 1900 || req.Year > 2100 {
 		w.WriteHeader(http.StatusBadRequest)
@@ -226,643 +364,336 @@ func handleFortune(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 计算100年运势
-	fortuneData := calculateHundredYearFortune(pBazi, req.Year)
+	// 计算运势, 不带 resolution start end 时是从出生年开始的100年
+	var fortuneData []fortune.KLine
+	query := r.URL.Query()
+	if query.Get("resolution") == "" && query.Get("start") == "" && query.Get("end") == "" {
+		fortuneData = model.Calculate(pBazi, req.Year, 100)
+	} else {
+		var errMsg string
+		fortuneData, errMsg = calculateTimeline(model, pBazi, req, query.Get("resolution"), query.Get("start"), query.Get("end"))
+		if errMsg != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(FortuneResponse{
+				Success: false,
+				Error:   errMsg,
+			})
+			return
+		}
+	}
+
+	// ?explain=1 时返回每年的评分因素, ?monthly=1 时返回每年的流月评分
+	explain := r.URL.Query().Get("explain") == "1"
+	monthly := r.URL.Query().Get("monthly") == "1"
+	for i := range fortuneData {
+		if !explain {
+			fortuneData[i].Factors = nil
+		}
+		if !monthly {
+			fortuneData[i].Months = nil
+		}
+	}
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(FortuneResponse{
 		Success: true,
+		Model:   model.Name(),
 		Data:    fortuneData,
 	})
 }
 
-// calculateHundredYearFortune 计算百年运势
-func calculateHundredYearFortune(pBazi *bazi.TBazi, birthYear int) []FortuneKLineData {
-	var fortuneData []FortuneKLineData
-
-	// 获取日主天干(命主五行)
-	dayGan := pBazi.SiZhu().DayZhu().Gan()
-	dayZhi := pBazi.SiZhu().DayZhu().Zhi()
-
-	// 获取八字四柱的天干地支
-	yearGan := pBazi.SiZhu().YearZhu().Gan()
-	yearZhi := pBazi.SiZhu().YearZhu().Zhi()
-	monthGan := pBazi.SiZhu().MonthZhu().Gan()
-	monthZhi := pBazi.SiZhu().MonthZhu().Zhi()
-	hourGan := pBazi.SiZhu().HourZhu().Gan()
-	hourZhi := pBazi.SiZhu().HourZhu().Zhi()
-
-	// 计算八字五行力量
-	baziPower := calculateBaziPower(yearGan, monthGan, dayGan, hourGan, yearZhi, monthZhi, dayZhi, hourZhi)
+// handleWuXing 百年五行强度走势, 每年是命局加上大运流年
+func handleWuXing(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
-	// 获取起运年龄（起运日期年份 - 出生年份）
-	qiYunAge := pBazi.QiYunDate().Year() - birthYear
-	if qiYunAge < 0 {
-		qiYunAge = 0
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "只支持 POST 请求",
+		})
+		return
 	}
 
-	// 获取大运信息
-	daYun := pBazi.DaYun()
-
-	// 计算命盘基础分（用于判断命格强弱）
-	baseScore := calculateBaseScore(baziPower, dayGan)
-
-	// 用于保存前一年的收盘价，使K线连续
-	var prevClose float64 = baseScore
-
-	// 计算100年运势
-	for i := 0; i < 100; i++ {
-		currentAge := i
-		year := birthYear + i
-
-		// 计算当前年龄对应的大运
-		var dayunIndex int
-		var dayunZhu *bazi.TZhu
-		var inDaYun bool = false
-
-		if currentAge < qiYunAge {
-			// 未起运阶段，使用月柱作为大运
-			dayunIndex = -1
-			dayunZhu = pBazi.SiZhu().MonthZhu()
-		} else {
-			// 已起运，计算大运索引
-			dayunIndex = (currentAge - qiYunAge) / 10
-			if dayunIndex >= 12 {
-				dayunIndex = 11 // 最多12步大运
-			}
-			dayunZhu = daYun.Zhu(dayunIndex)
-			inDaYun = true
-		}
-
-		// 获取大运天干地支
-		dayunGan := dayunZhu.Gan()
-		dayunZhi := dayunZhu.Zhi()
-		dayunShenSha := dayunZhu.ShenSha() // 大运神煞
-
-		// 计算流年天干地支
-		liuNianGan := getGanByYear(year)
-		liuNianZhi := getZhiByYear(year)
-
-		// 计算流年神煞
-		liuNianShenSha := bazi.CalcShenSha(dayGan, dayZhi, liuNianGan, liuNianZhi, "流年")
-
-		// 在当前大运中的年数
-		var dayunYearProgress int
-		if inDaYun {
-			dayunYearProgress = (currentAge - qiYunAge) % 10
-		} else {
-			dayunYearProgress = currentAge
-		}
-
-		// 计算该年运势评分（加入神煞影响）
-		yearScore := calculateYearFortuneAdvanced(
-			dayGan, dayZhi,
-			dayunGan, dayunZhi,
-			liuNianGan, liuNianZhi,
-			baziPower, baseScore,
-			dayunIndex, dayunYearProgress,
-			currentAge, inDaYun,
-			dayunShenSha, liuNianShenSha,
-		)
-
-		// 生成K线数据 - 让K线更加平滑连续
-		// 年初运势：受前一年影响，平滑过渡
-		open := prevClose*0.3 + yearScore*0.7
-
-		// 年末运势：向下一年过渡
-		nextYearBase := yearScore
-		if i < 99 {
-			// 预估下一年趋势
-			nextAge := i + 1
-			var nextDayunIndex int
-			if nextAge < qiYunAge {
-				nextDayunIndex = -1
-			} else {
-				nextDayunIndex = (nextAge - qiYunAge) / 10
-				if nextDayunIndex >= 12 {
-					nextDayunIndex = 11
-				}
-			}
-			// 如果即将换大运，运势波动加大
-			if nextDayunIndex != dayunIndex && inDaYun {
-				nextYearBase = yearScore * 0.9 // 换运期略有下降
-			}
-		}
-		close := yearScore*0.7 + nextYearBase*0.3
-
-		// 年内最高点：受吉神影响
-		high := yearScore + calculateMonthlyHighLow(year, liuNianGan, liuNianZhi, dayGan, true)
-
-		// 年内最低点：受凶神影响
-		low := yearScore + calculateMonthlyHighLow(year, liuNianGan, liuNianZhi, dayGan, false)
-
-		// 确保数值在合理范围内(0-100)
-		open = clamp(open, 0, 100)
-		close = clamp(close, 0, 100)
-		high = clamp(high, 0, 100)
-		low = clamp(low, 0, 100)
-		yearScore = clamp(yearScore, 0, 100)
-
-		// 确保K线数据的逻辑正确性
-		// 首先确保high是最大值，low是最小值
-		if high < open {
-			high = open
-		}
-		if high < close {
-			high = close
-		}
-		if low > open {
-			low = open
-		}
-		if low > close {
-			low = close
-		}
-		
-		// 最关键：确保low <= high，如果反了就交换
-		if low > high {
-			low, high = high, low
-		}
-
-		fortuneData = append(fortuneData, FortuneKLineData{
-			Year:  year,
-			Open:  roundFloat(open, 2),
-			Close: roundFloat(close, 2),
-			High:  roundFloat(high, 2),
-			Low:   roundFloat(low, 2),
-			Score: roundFloat(yearScore, 2),
+	var req BaziRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "无效的请求格式: " + err.Error(),
 		})
+		return
+	}
 
-		// 保存本年收盘价，作为下一年开盘价的参考
-		prevClose = close
+	// 验证输入
+	if req.Year < 1900 || req.Year > 2100 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "年份应在 1900-2100 之间",
+		})
+		return
 	}
 
-	return fortuneData
-}
+	// 计算八字
+	pBazi := bazi.GetBazi(req.Year, req.Month, req.Day, req.Hour, req.Minute, req.Second, req.Sex)
+	if pBazi == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "八字计算失败",
+		})
+		return
+	}
 
-// calculateBaziPower 计算八字五行力量分布
-func calculateBaziPower(yearGan, monthGan, dayGan, hourGan *bazi.TGan, 
-	yearZhi, monthZhi, dayZhi, hourZhi *bazi.TZhi) map[string]float64 {
-	power := map[string]float64{
-		"金": 0.0,
-		"木": 0.0,
-		"水": 0.0,
-		"火": 0.0,
-		"土": 0.0,
-	}
-
-	// 天干力量(每个天干10分)
-	addWuXingPower(power, yearGan.ToWuXing().String(), 10.0)
-	addWuXingPower(power, monthGan.ToWuXing().String(), 12.0) // 月柱权重更大
-	addWuXingPower(power, dayGan.ToWuXing().String(), 15.0)   // 日主权重最大
-	addWuXingPower(power, hourGan.ToWuXing().String(), 10.0)
-
-	// 地支力量(每个地支8分)
-	addWuXingPower(power, yearZhi.ToWuXing().String(), 8.0)
-	addWuXingPower(power, monthZhi.ToWuXing().String(), 10.0) // 月令权重更大
-	addWuXingPower(power, dayZhi.ToWuXing().String(), 8.0)
-	addWuXingPower(power, hourZhi.ToWuXing().String(), 8.0)
-
-	return power
-}
+	// weighted=1 时按旺相休囚死和人元司令加权
+	weighted := r.URL.Query().Get("weighted") == "1"
+	pXiYong := pBazi.SiZhu().XiYong()
+	if weighted {
+		pXiYong = pXiYong.Weighted()
+	}
 
-// addWuXingPower 添加五行力量
-func addWuXingPower(power map[string]float64, wuXing string, value float64) {
-	if _, ok := power[wuXing]; ok {
-		power[wuXing] += value
+	// 构建响应数据, 强度的顺序都是金木水火土
+	data := map[string]interface{}{
+		"elements": []string{"金", "木", "水", "火", "土"},
+		"natal":    pXiYong.WuXingList(),
+		"series":   fortune.WuXingSeries(pBazi, req.Year, 100, weighted),
 	}
-}
 
-// calculateBaseScore 计算命盘基础分（判断命格强弱）
-func calculateBaseScore(baziPower map[string]float64, dayGan *bazi.TGan) float64 {
-	// 计算五行总力量
-	totalPower := 0.0
-	for _, power := range baziPower {
-		totalPower += power
-	}
-
-	// 日主五行力量
-	dayWuXing := dayGan.ToWuXing().String()
-	dayPower := baziPower[dayWuXing]
-
-	// 计算生日主的五行力量（印星）
-	var supportPower float64
-	switch dayWuXing {
-	case "金":
-		supportPower = baziPower["土"] // 土生金
-	case "木":
-		supportPower = baziPower["水"] // 水生木
-	case "水":
-		supportPower = baziPower["金"] // 金生水
-	case "火":
-		supportPower = baziPower["木"] // 木生火
-	case "土":
-		supportPower = baziPower["火"] // 火生土
-	}
-
-	// 日主及其帮扶力量占比
-	// 帮扶力量 = 日主本身 + 同类五行（比劫） + 印星
-	// 这里dayPower已经包含了同类五行，所以再加一次就相当于：日主+比劫+印星
-	selfRatio := (dayPower*2 + supportPower) / totalPower
-
-	// 根据日主强弱确定基础分
-	// 身旺（自身强）：基础分稍高，但需要制衡
-	// 身弱（自身弱）：基础分中等，需要扶持
-	baseScore := 50.0
-	if selfRatio > 0.4 {
-		baseScore = 55.0 // 身旺
-	} else if selfRatio < 0.25 {
-		baseScore = 45.0 // 身弱
-	}
-
-	return baseScore
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(BaziResponse{
+		Success: true,
+		Data:    data,
+	})
 }
 
-// calculateYearFortuneAdvanced 高级运势计算（综合大运、流年、命盘、神煞）
-func calculateYearFortuneAdvanced(
-	dayGan *bazi.TGan, dayZhi *bazi.TZhi,
-	dayunGan *bazi.TGan, dayunZhi *bazi.TZhi,
-	liuNianGan *bazi.TGan, liuNianZhi *bazi.TZhi,
-	baziPower map[string]float64, baseScore float64,
-	dayunIndex int, dayunYearProgress int,
-	age int, inDaYun bool,
-	dayunShenSha *bazi.TShenSha, liuNianShenSha *bazi.TShenSha) float64 {
-
-	score := baseScore
-
-	dayWuXing := dayGan.ToWuXing().String()
-	dayunGanWuXing := dayunGan.ToWuXing().String()
-	dayunZhiWuXing := dayunZhi.ToWuXing().String()
-	liuNianGanWuXing := liuNianGan.ToWuXing().String()
-	liuNianZhiWuXing := liuNianZhi.ToWuXing().String()
-
-	// 1. 大运对日主的影响（权重35%，为神煞留出空间）
-	dayunGanScore := calculateShengKeScore(dayWuXing, dayunGanWuXing)
-	dayunZhiScore := calculateShengKeScore(dayWuXing, dayunZhiWuXing)
-	if inDaYun {
-		score += dayunGanScore * 0.25
-		score += dayunZhiScore * 0.20
-	} else {
-		// 未起运期，影响减半
-		score += dayunGanScore * 0.12
-		score += dayunZhiScore * 0.10
+// calculateTimeline 按 ?resolution=decade|year|month|day&start=2024-01-01&end=2024-03-31 计算运势K线
+// start 默认是出生日期, end 默认大运流年到100岁, 流月一年, 流日一个月, 出错时返回错误信息
+func calculateTimeline(model fortune.FortuneModel, pBazi *bazi.TBazi, req BaziRequest, strResolution, strStart, strEnd string) ([]fortune.KLine, string) {
+	timelineModel, ok := model.(fortune.TimelineModel)
+	if !ok {
+		return nil, fmt.Sprintf("运势模型 %s 不支持 resolution", model.Name())
 	}
 
-	// 2. 流年对日主的影响（权重25%）
-	liuNianGanScore := calculateShengKeScore(dayWuXing, liuNianGanWuXing)
-	liuNianZhiScore := calculateShengKeScore(dayWuXing, liuNianZhiWuXing)
-	score += liuNianGanScore * 0.15
-	score += liuNianZhiScore * 0.10 // 改为0.10，使总权重为25%
-
-	// 3. 大运与流年的互动关系（12%）
-	// 天干合化
-	if checkTianGanHe(dayunGan, liuNianGan) {
-		score += 7.0 // 天干相合为吉
-	}
-	// 地支三合、六合
-	if checkDiZhiHe(dayunZhi, liuNianZhi) {
-		score += 5.0 // 地支相合为吉
-	}
-	// 天克地冲
-	if checkTianGanChong(dayunGan, liuNianGan) {
-		score -= 7.0 // 天干相冲为凶
-	}
-	if checkDiZhiChong(dayunZhi, liuNianZhi) {
-		score -= 9.0 // 地支相冲为大凶
+	resolution, ok := fortune.ParseResolution(strResolution)
+	if !ok {
+		return nil, "resolution 只能是 decade year month day"
 	}
 
-	// 4. 流年与命盘的互动（8%）
-	// 流年与日柱的关系
-	if checkDiZhiChong(liuNianZhi, dayZhi) {
-		score -= 6.0 // 冲日支，身体、事业不顺
+	pStart := bazi.NewSolarDate(req.Year, req.Month, req.Day, 0, 0, 0)
+	if strStart != "" {
+		pStart = parseDate(strStart)
 	}
-	if checkDiZhiHe(liuNianZhi, dayZhi) {
-		score += 4.0 // 合日支，贵人相助
+	if pStart == nil {
+		return nil, "开始日期无效, 格式为 2024-01-01"
 	}
 
-	// 5. 大运神煞影响（8%权重）
-	if dayunShenSha != nil {
-		shenShaScore := calculateShenShaScore(dayunShenSha)
-		score += shenShaScore * 0.6 // 大运神煞影响持续10年，系数0.6
+	var pEnd *bazi.TSolarDate
+	switch {
+	case strEnd != "":
+		pEnd = parseDate(strEnd)
+	case resolution == fortune.ResolutionMonth:
+		pEnd = bazi.NewSolarDate(pStart.Year()+1, pStart.Month(), 1, 0, 0, 0).Add(-24 * time.Hour)
+	case resolution == fortune.ResolutionDay:
+		pEnd = pStart.Add(29 * 24 * time.Hour)
+	default:
+		pEnd = bazi.NewSolarDate(req.Year+99, 12, 31, 0, 0, 0)
 	}
-
-	// 6. 流年神煞影响（10%权重）
-	if liuNianShenSha != nil {
-		shenShaScore := calculateShenShaScore(liuNianShenSha)
-		score += shenShaScore * 0.8 // 流年神煞影响当年，系数0.8
+	// 没传结束日期时默认的范围不能超出历表
+	if strEnd == "" && pEnd != nil && pEnd.Year() > 2100 {
+		pEnd = bazi.NewSolarDate(2100, 12, 31, 0, 0, 0)
 	}
-
-	// 7. 八字五行平衡度（约3-5%影响）
-	balanceScore := calculateBalanceScore(baziPower, liuNianGanWuXing)
-	score += balanceScore * 0.3 // 返回-5或8或0，实际影响-1.5~2.4分
-
-	// 8. 大运内部进程影响（约1.5%影响）
-	// 大运前5年和后5年运势不同
-	if inDaYun {
-		if dayunYearProgress < 5 {
-			// 大运前5年，天干主事
-			score += 1.5
-		} else {
-			// 大运后5年，地支主事
-			score += 0.8
-		}
+	if pEnd == nil || pEnd.Before(pStart) || pStart.Year() < 1900 || pEnd.Year() > 2100 {
+		return nil, "日期范围无效, 格式为 2024-01-01, 年份应在 1900-2100 之间"
 	}
 
-	// 9. 年龄阶段生命周期影响（约3-6%影响）
-	ageEffect := calculateAgeEffect(age) // 返回0-10
-	score += ageEffect * 0.3 // 实际影响0-3分
-
-	// 10. 换大运的交接期（特殊处理）
-	if inDaYun && dayunYearProgress == 0 && dayunIndex > 0 {
-		score -= 4.0 // 换运之年，运势波动大，通常不利
+	// 流月流日要逐天计算, 限制一下范围
+	switch nDays := pStart.GetDiffDays(pEnd); {
+	case resolution == fortune.ResolutionMonth && nDays > 3660:
+		return nil, "流月的日期范围不能超过十年"
+	case resolution == fortune.ResolutionDay && nDays > 366:
+		return nil, "流日的日期范围不能超过一年"
 	}
 
-	return clamp(score, 15, 95)
+	return timelineModel.Timeline(pBazi, req.Year, resolution, pStart, pEnd), ""
 }
 
-// calculateShenShaScore 计算神煞评分
-func calculateShenShaScore(shenSha *bazi.TShenSha) float64 {
-	if shenSha == nil || shenSha.Count() == 0 {
-		return 0.0
-	}
-
-	score := 0.0
-	shenShaList := shenSha.GetList()
-
-	// 定义神煞权重
-	shenShaWeights := map[string]float64{
-		// 吉神（正分）
-		"天乙贵人": 12.0, // 最重要的吉神
-		"天德贵人": 10.0,
-		"月德贵人": 9.0,
-		"文昌贵人": 8.0,
-		"禄神":    8.0,
-		"将星":    6.0,
-		"华盖":    4.0, // 半吉半凶，艺术天赋但也主孤独
-		"驿马":    5.0, // 走动、变化
-
-		// 凶神（负分）
-		"羊刃":    -8.0,  // 性格刚烈，易有血光
-		"孤辰":    -6.0,  // 孤独
-		"寡宿":    -6.0,  // 孤独
-		"劫煞":    -7.0,  // 破财
-		"亡神":    -7.0,  // 疾病、意外
-		"天罗地网": -9.0,  // 困顿、受阻
-		"桃花":    3.0,   // 半吉半凶，异性缘好但也主风流
-	}
-
-	// 累加神煞分数
-	for _, ss := range shenShaList {
-		if weight, ok := shenShaWeights[ss]; ok {
-			score += weight
-		}
-	}
+// handleMatch 合婚, 比较两个八字
+func handleMatch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
-	// 特殊组合加成
-	// 如果同时有天乙贵人和天德贵人，额外加分
-	hasTianYi := false
-	hasTianDe := false
-	for _, ss := range shenShaList {
-		if ss == "天乙贵人" {
-			hasTianYi = true
-		}
-		if ss == "天德贵人" {
-			hasTianDe = true
-		}
-	}
-	if hasTianYi && hasTianDe {
-		score += 5.0 // 双贵人加成
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "只支持 POST 请求",
+		})
+		return
 	}
 
-	// 如果吉神多于凶神，额外加分
-	jiShenCount := shenSha.GetJiShenCount()
-	xiongShenCount := shenSha.GetXiongShenCount()
-	if jiShenCount > xiongShenCount && jiShenCount >= 2 {
-		score += float64(jiShenCount-xiongShenCount) * 2.0
+	var req MatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "无效的请求格式: " + err.Error(),
+		})
+		return
 	}
 
-	return clamp(score, -20, 30) // 神煞评分范围-20到30
-}
-
-// calculateMonthlyHighLow 计算年内高低点（模拟月份波动）
-func calculateMonthlyHighLow(year int, liuNianGan *bazi.TGan, liuNianZhi *bazi.TZhi, 
-	dayGan *bazi.TGan, isHigh bool) float64 {
-	
-	dayWuXing := dayGan.ToWuXing().String()
-	liuNianWuXing := liuNianGan.ToWuXing().String()
-
-	// 基础波动幅度
-	baseWave := 8.0
-
-	// 根据五行关系调整波动幅度
-	shengKeScore := calculateShengKeScore(dayWuXing, liuNianWuXing)
-	
-	if isHigh {
-		// 计算年内最高点（应该返回正数）
-		if shengKeScore > 0 {
-			return baseWave + shengKeScore*0.3
-		}
-		return baseWave
-	} else {
-		// 计算年内最低点（应该返回负数）
-		if shengKeScore < 0 {
-			return shengKeScore * 0.3
-		}
-		return -baseWave
+	// 验证输入
+	if req.Person1.Year < 1900 || req.Person1.Year > 2100 || req.Person2.Year < 1900 || req.Person2.Year > 2100 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "年份应在 1900-2100 之间",
+		})
+		return
 	}
-}
-
-// checkTianGanHe 检查天干是否相合
-func checkTianGanHe(gan1 *bazi.TGan, gan2 *bazi.TGan) bool {
-	// 天干五合：甲己合、乙庚合、丙辛合、丁壬合、戊癸合
-	// 甲=0,乙=1,丙=2,丁=3,戊=4,己=5,庚=6,辛=7,壬=8,癸=9
-	v1, v2 := gan1.Value(), gan2.Value()
-	sum := v1 + v2
-	// 甲己合(0+5=5)、乙庚合(1+6=7)、丙辛合(2+7=9)、丁壬合(3+8=11)、戊癸合(4+9=13)
-	return sum == 5 || sum == 7 || sum == 9 || sum == 11 || sum == 13
-}
 
-// checkTianGanChong 检查天干是否相冲
-func checkTianGanChong(gan1 *bazi.TGan, gan2 *bazi.TGan) bool {
-	// 天干相冲（相克严重）
-	w1 := gan1.ToWuXing().String()
-	w2 := gan2.ToWuXing().String()
-	
-	// 阳干冲阳干，阴干冲阴干
-	if (gan1.Value()%2) == (gan2.Value()%2) {
-		return isWuXingKe(w1, w2) || isWuXingKe(w2, w1)
+	// 计算八字
+	pBazi1 := bazi.GetBazi(req.Person1.Year, req.Person1.Month, req.Person1.Day, req.Person1.Hour, req.Person1.Minute, req.Person1.Second, req.Person1.Sex)
+	pBazi2 := bazi.GetBazi(req.Person2.Year, req.Person2.Month, req.Person2.Day, req.Person2.Hour, req.Person2.Minute, req.Person2.Second, req.Person2.Sex)
+	if pBazi1 == nil || pBazi2 == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "八字计算失败",
+		})
+		return
 	}
-	return false
-}
 
-// checkDiZhiHe 检查地支是否相合
-func checkDiZhiHe(zhi1 *bazi.TZhi, zhi2 *bazi.TZhi) bool {
-	// 地支六合：子丑合、寅亥合、卯戌合、辰酉合、巳申合、午未合
-	// 子=0,丑=1,寅=2,卯=3,辰=4,巳=5,午=6,未=7,申=8,酉=9,戌=10,亥=11
-	v1, v2 := zhi1.Value(), zhi2.Value()
-	
-	// 检查所有六合配对
-	heMap := map[int]int{
-		0:  1,  // 子丑合
-		1:  0,  // 丑子合
-		2:  11, // 寅亥合
-		11: 2,  // 亥寅合
-		3:  10, // 卯戌合
-		10: 3,  // 戌卯合
-		4:  9,  // 辰酉合
-		9:  4,  // 酉辰合
-		5:  8,  // 巳申合
-		8:  5,  // 申巳合
-		6:  7,  // 午未合
-		7:  6,  // 未午合
-	}
-	
-	return heMap[v1] == v2
-}
+	pHeHun := bazi.NewHeHun(pBazi1, pBazi2)
 
-// checkDiZhiChong 检查地支是否相冲
-func checkDiZhiChong(zhi1 *bazi.TZhi, zhi2 *bazi.TZhi) bool {
-	// 地支六冲：子午、丑未、寅申、卯酉、辰戌、巳亥
-	v1, v2 := zhi1.Value(), zhi2.Value()
-	diff := v1 - v2
-	if diff < 0 {
-		diff = -diff
+	// 构建响应数据
+	data := map[string]interface{}{
+		"score":   pHeHun.Score(),
+		"level":   pHeHun.Level(),
+		"reasons": pHeHun.Items(),
+		"person1": pBazi1.SiZhu().String(),
+		"person2": pBazi2.SiZhu().String(),
 	}
-	// 相冲的地支相差6
-	return diff == 6
-}
 
-// isWuXingKe 判断五行是否相克
-func isWuXingKe(wuXing1, wuXing2 string) bool {
-	keMap := map[string]string{
-		"木": "土",
-		"土": "水",
-		"水": "火",
-		"火": "金",
-		"金": "木",
-	}
-	return keMap[wuXing1] == wuXing2
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(BaziResponse{
+		Success: true,
+		Data:    data,
+	})
 }
 
-// calculateShengKeScore 计算五行生克关系评分
-func calculateShengKeScore(wuXing1, wuXing2 string) float64 {
-	// 相生关系映射
-	shengMap := map[string]string{
-		"木": "火",
-		"火": "土",
-		"土": "金",
-		"金": "水",
-		"水": "木",
-	}
+// handleZeRi 择日, 在日期范围内为事项挑选吉日
+func handleZeRi(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
-	// 相克关系映射
-	keMap := map[string]string{
-		"木": "土",
-		"土": "水",
-		"水": "火",
-		"火": "金",
-		"金": "木",
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "只支持 POST 请求",
+		})
+		return
 	}
 
-	// 被生（他生我）
-	if shengMap[wuXing2] == wuXing1 {
-		return 12.0 // 被生为大吉，有贵人相助
+	var req ZeRiRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "无效的请求格式: " + err.Error(),
+		})
+		return
 	}
 
-	// 生他人（我生他）
-	if shengMap[wuXing1] == wuXing2 {
-		return -3.0 // 生他人为泄气，消耗自身能量，不利
+	// 验证输入
+	pProfile := bazi.GetZeRiProfile(req.Event)
+	if pProfile == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   fmt.Sprintf("未知的事项 %q, 可选: %v", req.Event, bazi.GetZeRiProfileKeys()),
+		})
+		return
 	}
 
-	// 克他人（我克他）
-	if keMap[wuXing1] == wuXing2 {
-		return 8.0 // 我克他人得财，有力量控制局面
+	pStart := parseDate(req.Start)
+	pEnd := parseDate(req.End)
+	if pStart == nil || pEnd == nil || pEnd.Before(pStart) || pStart.Year() < 1900 || pEnd.Year() > 2100 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "日期范围无效, 格式为 2024-01-01, 年份应在 1900-2100 之间",
+		})
+		return
 	}
 
-	// 被克（他克我）
-	if keMap[wuXing2] == wuXing1 {
-		return -10.0 // 被克为大凶，受压制
+	if pStart.GetDiffDays(pEnd) > 366 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(BaziResponse{
+			Success: false,
+			Error:   "日期范围不能超过一年",
+		})
+		return
 	}
 
-	// 同类
-	if wuXing1 == wuXing2 {
-		return 6.0 // 比和,有帮助
+	// 计算八字
+	var baziList []*bazi.TBazi
+	for _, chart := range req.Charts {
+		pBazi := bazi.GetBazi(chart.Year, chart.Month, chart.Day, chart.Hour, chart.Minute, chart.Second, chart.Sex)
+		if pBazi == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(BaziResponse{
+				Success: false,
+				Error:   "八字计算失败",
+			})
+			return
+		}
+		baziList = append(baziList, pBazi)
 	}
 
-	return 0.0
-}
-
-// calculateBalanceScore 计算八字五行平衡度评分
-func calculateBalanceScore(baziPower map[string]float64, liuNianWuXing string) float64 {
-	// 计算总力量
-	total := 0.0
-	for _, power := range baziPower {
-		total += power
+	if req.Limit <= 0 {
+		req.Limit = 10
 	}
 
-	// 计算当前五行在八字中的占比
-	currentPower := baziPower[liuNianWuXing]
-	ratio := currentPower / total
+	pZeRi := bazi.NewZeRi(pProfile, pStart, pEnd, baziList...)
 
-	// 如果流年五行在八字中较弱,则流年补足为吉
-	if ratio < 0.15 {
-		return 8.0 // 补弱五行为吉
-	} else if ratio > 0.3 {
-		return -5.0 // 强者更强,过旺为凶
+	// 构建响应数据
+	data := map[string]interface{}{
+		"event": pProfile.Name,
+		"days":  pZeRi.Top(req.Limit),
 	}
 
-	return 0.0
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(BaziResponse{
+		Success: true,
+		Data:    data,
+	})
 }
 
-// calculateAgeEffect 计算年龄阶段影响
-func calculateAgeEffect(age int) float64 {
-	// 不同年龄段有不同的运势基调
-	switch {
-	case age < 10:
-		return 5.0 // 童年,纯真
-	case age < 20:
-		return 3.0 // 青少年,成长
-	case age < 30:
-		return 8.0 // 青年,上升期
-	case age < 40:
-		return 10.0 // 壮年,高峰期
-	case age < 50:
-		return 6.0 // 中年,稳定期
-	case age < 60:
-		return 4.0 // 中老年,下降期
-	case age < 70:
-		return 2.0 // 老年,平稳期
-	default:
-		return 0.0 // 晚年,淡泊期
+// parseDate 解析 2024-01-01 格式的日期, 无效时返回 nil
+func parseDate(strDate string) *bazi.TSolarDate {
+	var nYear, nMonth, nDay int
+	if _, err := fmt.Sscanf(strDate, "%d-%d-%d", &nYear, &nMonth, &nDay); err != nil {
+		return nil
 	}
+	return bazi.NewSolarDate(nYear, nMonth, nDay, 0, 0, 0)
 }
 
-// getGanByYear 根据年份获取天干
-func getGanByYear(year int) *bazi.TGan {
-	// 计算天干索引(以1900年为庚年起点)
-	ganIndex := (year - 1900 + 6) % 10
-	return bazi.NewGan(ganIndex)
-}
-
-// getZhiByYear 根据年份获取地支
-func getZhiByYear(year int) *bazi.TZhi {
-	// 计算地支索引(以1900年为子年起点)
-	zhiIndex := (year - 1900 + 0) % 12
-	return bazi.NewZhi(zhiIndex)
-}
+// registerFortuneModels 注册运势模型
+// 环境变量 FORTUNE_CONFIG 指向的配置文件里每一套权重注册成一个模型, 用 ?model=名字 选用
+// 没有配置 default 的话用内置的默认权重
+func registerFortuneModels() {
+	fortune.Register(fortune.NewDefaultModel(fortune.DefaultModelName, fortune.DefaultConfig()))
 
-// clamp 限制数值范围
-func clamp(value, min, max float64) float64 {
-	if value < min {
-		return min
+	path := os.Getenv("FORTUNE_CONFIG")
+	if path == "" {
+		return
 	}
-	if value > max {
-		return max
+	configList, err := fortune.LoadConfigFile(path)
+	if err != nil {
+		log.Fatalf("读取运势配置失败: %v", err)
 	}
-	return value
-}
-
-// roundFloat 四舍五入到指定小数位
-func roundFloat(value float64, precision int) float64 {
-	ratio := math.Pow(10, float64(precision))
-	return math.Round(value*ratio) / ratio
+	for name, cfg := range configList {
+		fortune.Register(fortune.NewDefaultModel(name, cfg))
+	}
+	log.Printf("运势模型: %v", fortune.Names())
 }
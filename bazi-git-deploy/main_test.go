@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"bazi/fortune"
+	bazi "github.com/warrially/BaziGo"
+)
+
+// TestCalculateTimeline 没传结束日期时默认范围截到2100年, 传了超出的报错
+func TestCalculateTimeline(t *testing.T) {
+	req := BaziRequest{Year: 2030, Month: 5, Day: 17, Hour: 8, Sex: 1}
+	pBazi := bazi.GetBazi(req.Year, req.Month, req.Day, req.Hour, 0, 0, req.Sex)
+	model := fortune.NewDefaultModel(fortune.DefaultModelName, nil)
+
+	cases := []struct {
+		strResolution    string
+		strStart, strEnd string
+		nFirstYear       int
+		nLastYear        int
+		strErr           string
+	}{
+		// 默认到99岁是2129年, 截到2100年
+		{"year", "", "", 2030, 2100, ""},
+		{"", "2090-01-01", "", 2090, 2100, ""},
+		// 流月默认一年, 也截到2100年年底
+		{"month", "2100-12-01", "", 2100, 2100, ""},
+		{"year", "", "2101-01-01", 0, 0, "日期范围无效, 格式为 2024-01-01, 年份应在 1900-2100 之间"},
+		{"year", "2040-01-01", "2039-01-01", 0, 0, "日期范围无效, 格式为 2024-01-01, 年份应在 1900-2100 之间"},
+		{"week", "", "", 0, 0, "resolution 只能是 decade year month day"},
+	}
+	for _, c := range cases {
+		lineList, strErr := calculateTimeline(model, pBazi, req, c.strResolution, c.strStart, c.strEnd)
+		if strErr != c.strErr {
+			t.Errorf("%s %s-%s err = %q, want %q", c.strResolution, c.strStart, c.strEnd, strErr, c.strErr)
+			continue
+		}
+		if c.strErr != "" {
+			continue
+		}
+		if len(lineList) == 0 || lineList[0].Year != c.nFirstYear || lineList[len(lineList)-1].Year != c.nLastYear {
+			t.Errorf("%s %s-%s = %d 根, want %d-%d", c.strResolution, c.strStart, c.strEnd, len(lineList), c.nFirstYear, c.nLastYear)
+		}
+	}
+}